@@ -18,6 +18,7 @@ package v1
 
 import (
 	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -42,6 +43,95 @@ const (
 	ManualSubnetTaggingPolicy SubnetTaggingPolicy = "Manual"
 )
 
+// +kubebuilder:validation:Enum=Enabled;Disabled
+type PodReadinessGateInjectionPolicy string
+
+const (
+	// PodReadinessGateInjectionEnabled enables the injection of the readiness gates into the pods.
+	PodReadinessGateInjectionEnabled PodReadinessGateInjectionPolicy = "Enabled"
+
+	// PodReadinessGateInjectionDisabled disables the injection of the readiness gates into the pods.
+	PodReadinessGateInjectionDisabled PodReadinessGateInjectionPolicy = "Disabled"
+)
+
+// +kubebuilder:validation:Enum=Enabled;Disabled
+type DefaultLoadBalancerClassPolicy string
+
+const (
+	// DefaultLoadBalancerClassEnabled assigns the controller's load balancer class to the Services created without one.
+	DefaultLoadBalancerClassEnabled DefaultLoadBalancerClassPolicy = "Enabled"
+
+	// DefaultLoadBalancerClassDisabled leaves the Services created without a load balancer class untouched.
+	DefaultLoadBalancerClassDisabled DefaultLoadBalancerClassPolicy = "Disabled"
+)
+
+// +kubebuilder:validation:Enum=Enabled;Disabled
+type DefaultIngressClassPolicy string
+
+const (
+	// DefaultIngressClassEnabled makes the controller's IngressClass the default IngressClass of the cluster.
+	DefaultIngressClassEnabled DefaultIngressClassPolicy = "Enabled"
+
+	// DefaultIngressClassDisabled leaves the default IngressClass of the cluster unchanged.
+	DefaultIngressClassDisabled DefaultIngressClassPolicy = "Disabled"
+)
+
+// WebhookFailurePolicy specifies how the errors of a webhook call are handled by the API server.
+// +kubebuilder:validation:Enum=Fail;Ignore
+type WebhookFailurePolicy string
+
+const (
+	// WebhookFailurePolicyFail rejects the API request if the webhook call fails.
+	WebhookFailurePolicyFail WebhookFailurePolicy = "Fail"
+
+	// WebhookFailurePolicyIgnore lets the API request through if the webhook call fails.
+	WebhookFailurePolicyIgnore WebhookFailurePolicy = "Ignore"
+)
+
+// IngressScheme is the scheme of the load balancers provisioned for the Ingresses.
+// +kubebuilder:validation:Enum=internal;internet-facing
+type IngressScheme string
+
+const (
+	// IngressSchemeInternal provisions the load balancers in the internal subnets.
+	IngressSchemeInternal IngressScheme = "internal"
+
+	// IngressSchemeInternetFacing provisions the load balancers in the public subnets.
+	IngressSchemeInternetFacing IngressScheme = "internet-facing"
+)
+
+// IngressIPAddressType is the IP address type of the load balancers provisioned for the Ingresses.
+// +kubebuilder:validation:Enum=ipv4;dualstack
+type IngressIPAddressType string
+
+const (
+	// IngressIPAddressTypeIPv4 provisions the load balancers with IPv4 addresses only.
+	IngressIPAddressTypeIPv4 IngressIPAddressType = "ipv4"
+
+	// IngressIPAddressTypeDualStack provisions the load balancers with both IPv4 and IPv6 addresses.
+	IngressIPAddressTypeDualStack IngressIPAddressType = "dualstack"
+)
+
+// FeatureGateName is the name of a feature gate of the controller.
+// Only the feature gates supported by the controller version bundled with the operator are allowed.
+// +kubebuilder:validation:Enum=EnableIPTargetType;EnableRGTAPI;ListenerRulesTagging;WeightedTargetGroups;SubnetsClusterTagCheck;EndpointsFailOpen
+type FeatureGateName string
+
+const (
+	// FeatureGateEnableIPTargetType enables the "ip" target type for the Ingresses and Services.
+	FeatureGateEnableIPTargetType FeatureGateName = "EnableIPTargetType"
+	// FeatureGateEnableRGTAPI enables the use of the Resource Groups Tagging API for the resource lookups.
+	FeatureGateEnableRGTAPI FeatureGateName = "EnableRGTAPI"
+	// FeatureGateListenerRulesTagging enables the tagging of the listener rules.
+	FeatureGateListenerRulesTagging FeatureGateName = "ListenerRulesTagging"
+	// FeatureGateWeightedTargetGroups enables the weighted target groups for the forward actions.
+	FeatureGateWeightedTargetGroups FeatureGateName = "WeightedTargetGroups"
+	// FeatureGateSubnetsClusterTagCheck enables the check of the cluster tag during the subnet discovery.
+	FeatureGateSubnetsClusterTagCheck FeatureGateName = "SubnetsClusterTagCheck"
+	// FeatureGateEndpointsFailOpen enables the registration of the not ready endpoints when no ready endpoints are available.
+	FeatureGateEndpointsFailOpen FeatureGateName = "EndpointsFailOpen"
+)
+
 // AWSLoadBalancerControllerSpec defines the desired state of AWSLoadBalancerController.
 type AWSLoadBalancerControllerSpec struct {
 	// subnetTagging describes how the subnet tagging will be done by the operator.
@@ -58,6 +148,28 @@ type AWSLoadBalancerControllerSpec struct {
 	// +optional
 	SubnetTagging SubnetTaggingPolicy `json:"subnetTagging,omitempty"`
 
+	// subnets selects the subnets where the load balancers will be provisioned explicitly
+	// instead of discovering the subnets tagged with `kubernetes.io/cluster/${cluster-name}`.
+	// This is useful when the cluster tag cannot be put on the subnets, like in shared VPCs,
+	// or when the load balancers have to be restricted to a subset of the cluster subnets.
+	// The selected subnets must belong to the VPC of the cluster. When subnetTagging is "Auto",
+	// the operator tags the selected subnets with the role tags matching their selection.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Subnets *AWSLoadBalancerControllerSubnets `json:"subnets,omitempty"`
+
+	// vpcID is the ID of the VPC where the load balancers will be provisioned.
+	// When set, the VPC is not discovered from the `kubernetes.io/cluster/${cluster-name}` tag,
+	// which fails when the cluster is installed into a shared VPC or when the tag is only on the subnets.
+	// The VPC must exist and contain the subnets of the cluster, or the subnets selected
+	// with the subnets field. When omitted, the VPC of the cluster is discovered.
+	//
+	// +kubebuilder:validation:Pattern=`^vpc-[0-9a-f]+$`
+	// +kubebuilder:validation:Optional
+	// +optional
+	VPCID string `json:"vpcID,omitempty"`
+
 	// additionalResourceTags are the AWS tags that will be applied to all AWS resources managed by this
 	// controller. The managed AWS resources don't include the cluster subnets which are tagged by the operator.
 	// The addition of new tags as well as the update or removal of any existing tags
@@ -87,6 +199,55 @@ type AWSLoadBalancerControllerSpec struct {
 	// +optional
 	IngressClass string `json:"ingressClass,omitempty"`
 
+	// ingressClassParams specifies the settings enforced on all the Ingresses of the ingress class.
+	// When this field is set, the operator creates an IngressClassParams resource
+	// with the same name as the ingress class and links it from the IngressClass.
+	// The settings of the IngressClassParams take precedence over the annotations of the Ingresses.
+	// The IngressClassParams is removed when this field is unset.
+	// For more info see https://kubernetes-sigs.github.io/aws-load-balancer-controller/v2.4/guide/ingress/ingress_class/#ingressclassparams.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	IngressClassParams *AWSLoadBalancerIngressClassParams `json:"ingressClassParams,omitempty"`
+
+	// defaultIngressClass specifies whether the IngressClass created for the controller
+	// is the default IngressClass of the cluster.
+	// Allowed values are "Enabled" and "Disabled". The default value is "Disabled".
+	// When this field is set to "Enabled", the IngressClass is annotated with
+	// "ingressclass.kubernetes.io/is-default-class" and the Ingresses created without
+	// an ingress class are reconciled by the controller instead of the OpenShift router.
+	// Only one instance of the controller should enable this field.
+	//
+	// +kubebuilder:default:=Disabled
+	// +kubebuilder:validation:Optional
+	// +optional
+	DefaultIngressClass DefaultIngressClassPolicy `json:"defaultIngressClass,omitempty"`
+
+	// loadBalancerClass specifies the load balancer class of the Services of type LoadBalancer
+	// which the controller will reconcile into Network Load Balancers.
+	// The value will default to "service.k8s.aws/nlb". The instances of the controller must
+	// use different load balancer classes, an instance using the class of an older instance is not reconciled.
+	// For more info see https://kubernetes-sigs.github.io/aws-load-balancer-controller/v2.4/guide/service/nlb/#configuration.
+	//
+	// +kubebuilder:default:="service.k8s.aws/nlb"
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`
+	// +kubebuilder:validation:Optional
+	// +optional
+	LoadBalancerClass string `json:"loadBalancerClass,omitempty"`
+
+	// defaultLoadBalancerClass specifies whether the controller's load balancer class
+	// is assigned to the Services of type LoadBalancer created without a load balancer class.
+	// Allowed values are "Enabled" and "Disabled". The default value is "Disabled".
+	// When this field is set to "Enabled", the controller's service mutating webhook is registered
+	// and the Services of type LoadBalancer are served by the controller without any annotation,
+	// instead of the in-tree cloud provider. Only one instance of the controller should enable this field.
+	//
+	// +kubebuilder:default:=Disabled
+	// +kubebuilder:validation:Optional
+	// +optional
+	DefaultLoadBalancerClass DefaultLoadBalancerClassPolicy `json:"defaultLoadBalancerClass,omitempty"`
+
 	// config specifies further customization options for the controller's deployment spec.
 	//
 	// +kubebuilder:validation:Optional
@@ -129,6 +290,242 @@ type AWSLoadBalancerControllerSpec struct {
 	// +kubebuilder:validation:Optional
 	// +optional
 	CredentialsRequestConfig *AWSLoadBalancerCredentialsRequestConfig `json:"credentialsRequestConfig,omitempty"`
+
+	// featureGates is the list of the controller's feature gates to be enabled or disabled.
+	// The feature gates which are not listed keep the controller's default value
+	// except for "EnableIPTargetType" which is disabled unless enabled explicitly.
+	// The "ip" target type requires the pod IPs to be routable from the VPC,
+	// for more info see https://kubernetes-sigs.github.io/aws-load-balancer-controller/v2.4/guide/ingress/annotations/#target-type.
+	// For more info on the feature gates see
+	// https://kubernetes-sigs.github.io/aws-load-balancer-controller/v2.4/deploy/configurations/#feature-gates.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	FeatureGates []AWSLoadBalancerFeatureGate `json:"featureGates,omitempty"`
+
+	// podReadinessGateInjection specifies whether the controller injects the pod readiness gates.
+	// Allowed values are "Enabled" and "Disabled". The default value is "Disabled".
+	// When this field is set to "Enabled", the controller's pod mutating webhook is registered
+	// for the namespaces labeled with "elbv2.k8s.aws/pod-readiness-gate-inject=enabled".
+	// The pods created in these namespaces and targeted by a load balancer get a readiness gate
+	// which is satisfied only once the pod is registered and healthy in the load balancer's target group.
+	// This prevents the rolling updates from taking down all the healthy targets.
+	// For more info see https://kubernetes-sigs.github.io/aws-load-balancer-controller/v2.4/deploy/pod_readiness_gate.
+	//
+	// +kubebuilder:default:=Disabled
+	// +kubebuilder:validation:Optional
+	// +optional
+	PodReadinessGateInjection PodReadinessGateInjectionPolicy `json:"podReadinessGateInjection,omitempty"`
+
+	// webhooks customizes the registration of the controller's admission webhooks.
+	// By default, the webhooks fail closed and the Ingress, Pod and Service webhooks
+	// are not called for the platform namespaces: "default", "kube-system", "kube-public",
+	// "kube-node-lease" and the namespaces labeled with "openshift.io/cluster-monitoring=true".
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Webhooks *AWSLoadBalancerControllerWebhooks `json:"webhooks,omitempty"`
+
+	// alerts tunes the thresholds of the alerts raised for the controller.
+	// The alerts are shipped in a PrometheusRule which is created when
+	// the PrometheusRule CRD from the Prometheus operator is installed on the cluster.
+	// The default thresholds are used when this field is omitted.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Alerts *AWSLoadBalancerControllerAlerts `json:"alerts,omitempty"`
+}
+
+// AWSLoadBalancerIngressClassParams defines the settings enforced on the Ingresses of the ingress class.
+type AWSLoadBalancerIngressClassParams struct {
+	// scheme is the scheme of the load balancers provisioned for the Ingresses.
+	// Allowed values are "internal" and "internet-facing".
+	// When it's not set, the scheme is taken from the annotations of the Ingresses.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Scheme IngressScheme `json:"scheme,omitempty"`
+
+	// groupName puts all the Ingresses of the ingress class in the same ingress group,
+	// the Ingresses of the group share a single load balancer.
+	//
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^([a-z0-9][-a-z0-9.]*)?[a-z0-9]$`
+	// +kubebuilder:validation:Optional
+	// +optional
+	GroupName string `json:"groupName,omitempty"`
+
+	// namespaceSelector restricts the namespaces of the Ingresses allowed to use the ingress class.
+	// When it's not set, the Ingresses of all the namespaces are allowed.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// ipAddressType is the IP address type of the load balancers provisioned for the Ingresses.
+	// Allowed values are "ipv4" and "dualstack".
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	IPAddressType IngressIPAddressType `json:"ipAddressType,omitempty"`
+
+	// tags are the AWS tags applied to the AWS resources provisioned for the Ingresses,
+	// in addition to the additionalResourceTags.
+	//
+	// +kubebuilder:validation:MaxItems=24
+	// +kubebuilder:validation:Optional
+	// +optional
+	// +listType=map
+	// +listMapKey=key
+	Tags []AWSResourceTag `json:"tags,omitempty"`
+
+	// loadBalancerAttributes are the attributes of the load balancers provisioned for the Ingresses.
+	// For the available attributes see https://docs.aws.amazon.com/elasticloadbalancing/latest/application/application-load-balancers.html#load-balancer-attributes.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	// +listType=map
+	// +listMapKey=key
+	LoadBalancerAttributes []AWSLoadBalancerAttribute `json:"loadBalancerAttributes,omitempty"`
+}
+
+// AWSLoadBalancerAttribute is an attribute of the load balancers provisioned by the controller.
+type AWSLoadBalancerAttribute struct {
+	// key is the key of the attribute.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Key string `json:"key"`
+
+	// value is the value of the attribute.
+	//
+	// +kubebuilder:validation:Required
+	// +required
+	Value string `json:"value"`
+}
+
+// AWSLoadBalancerControllerWebhooks customizes the admission webhooks of the controller.
+type AWSLoadBalancerControllerWebhooks struct {
+	// ingress customizes the validating webhook for the Ingresses.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Ingress *AWSLoadBalancerWebhookConfig `json:"ingress,omitempty"`
+
+	// targetGroupBinding customizes the validating and mutating webhooks for the TargetGroupBindings.
+	// The object selector is combined with the selector of the TargetGroupBindings assigned to the instance.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	TargetGroupBinding *AWSLoadBalancerWebhookConfig `json:"targetGroupBinding,omitempty"`
+
+	// pod customizes the mutating webhook which injects the pod readiness gates.
+	// It has no effect unless podReadinessGateInjection is "Enabled".
+	// The namespace selector is combined with the pod readiness gate injection label.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Pod *AWSLoadBalancerWebhookConfig `json:"pod,omitempty"`
+
+	// service customizes the mutating webhook which assigns the default load balancer class.
+	// It has no effect unless defaultLoadBalancerClass is "Enabled".
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Service *AWSLoadBalancerWebhookConfig `json:"service,omitempty"`
+}
+
+// AWSLoadBalancerWebhookConfig customizes the registration of an admission webhook.
+type AWSLoadBalancerWebhookConfig struct {
+	// failurePolicy specifies how the errors of the webhook calls are handled.
+	// Allowed values are "Fail" and "Ignore". The default value is "Fail".
+	// With "Ignore", the requests are admitted without being validated or mutated
+	// when the controller is not available.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	FailurePolicy WebhookFailurePolicy `json:"failurePolicy,omitempty"`
+
+	// namespaceSelector selects the namespaces of the objects sent to the webhook.
+	// It replaces the default selector which excludes the platform namespaces.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// objectSelector selects the objects sent to the webhook based on their labels.
+	// The objects managed by the operator are always excluded from the Pod and Service webhooks.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	ObjectSelector *metav1.LabelSelector `json:"objectSelector,omitempty"`
+
+	// timeoutSeconds is the timeout of the webhook calls.
+	// The value must be between 1 and 30 seconds. The default value is 10 seconds.
+	//
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=30
+	// +kubebuilder:validation:Optional
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// AWSLoadBalancerControllerAlerts defines the thresholds of the alerts raised for the controller.
+type AWSLoadBalancerControllerAlerts struct {
+	// deploymentUnavailableMinutes is the number of minutes for which
+	// the controller deployment has to be unavailable before an alert is raised.
+	//
+	// +kubebuilder:default:=15
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Optional
+	// +optional
+	DeploymentUnavailableMinutes int32 `json:"deploymentUnavailableMinutes,omitempty"`
+
+	// credentialsSecretNotProvisionedMinutes is the number of minutes for which
+	// the credentials secret has to be missing before an alert is raised.
+	//
+	// +kubebuilder:default:=15
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Optional
+	// +optional
+	CredentialsSecretNotProvisionedMinutes int32 `json:"credentialsSecretNotProvisionedMinutes,omitempty"`
+
+	// subnetsSyncFailingMinutes is the number of minutes for which
+	// the discovery or the tagging of the subnets has to fail before an alert is raised.
+	//
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Optional
+	// +optional
+	SubnetsSyncFailingMinutes int32 `json:"subnetsSyncFailingMinutes,omitempty"`
+
+	// awsAPIErrorRatePercent is the percentage of the AWS API calls made by the controller
+	// which have to fail over the last 15 minutes before an alert is raised.
+	//
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=100
+	// +kubebuilder:validation:Optional
+	// +optional
+	AWSAPIErrorRatePercent int32 `json:"awsAPIErrorRatePercent,omitempty"`
+}
+
+// AWSLoadBalancerFeatureGate enables or disables a feature gate of the controller.
+type AWSLoadBalancerFeatureGate struct {
+	// name is the name of the feature gate.
+	//
+	// +kubebuilder:validation:Required
+	// +required
+	Name FeatureGateName `json:"name"`
+
+	// enabled indicates whether the feature gate is enabled.
+	//
+	// +kubebuilder:validation:Required
+	// +required
+	Enabled bool `json:"enabled"`
 }
 
 // AWSResourceTag is a tag to apply to AWS resources created by the controller.
@@ -155,6 +552,69 @@ type AWSResourceTag struct {
 	Value string `json:"value"`
 }
 
+// AWSLoadBalancerControllerSubnets selects the public and internal subnets of the controller.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.public) || has(self.internal)",message="at least one of public or internal must be set"
+type AWSLoadBalancerControllerSubnets struct {
+	// public selects the subnets used by the internet-facing load balancers.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Public *AWSSubnetSelection `json:"public,omitempty"`
+
+	// internal selects the subnets used by the internal load balancers.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Internal *AWSSubnetSelection `json:"internal,omitempty"`
+}
+
+// AWSSubnetSelection selects subnets either by their IDs or by their tags.
+//
+// +kubebuilder:validation:XValidation:rule="has(self.ids) != has(self.tags)",message="exactly one of ids or tags must be set"
+type AWSSubnetSelection struct {
+	// ids is the list of the IDs of the selected subnets.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=100
+	// +kubebuilder:validation:items:Pattern=`^subnet-[0-9a-f]+$`
+	// +listType=set
+	// +optional
+	IDs []string `json:"ids,omitempty"`
+
+	// tags selects the subnets which have all the given tags.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=10
+	// +listType=map
+	// +listMapKey=key
+	// +optional
+	Tags []AWSSubnetTagSelector `json:"tags,omitempty"`
+}
+
+// AWSSubnetTagSelector matches the subnets having a tag.
+type AWSSubnetTagSelector struct {
+	// key is the key of the tag.
+	//
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=128
+	// +kubebuilder:validation:Pattern=`^[0-9A-Za-z_.:/=+-@]+$`
+	// +required
+	Key string `json:"key"`
+
+	// value is the value of the tag.
+	// The subnets having the tag with any value are matched if it's empty.
+	//
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxLength=256
+	// +kubebuilder:validation:Pattern=`^[0-9A-Za-z_.:/=+-@]*$`
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
 // AWSLoadBalancerDeploymentConfig defines customization options for the controller's deployment spec.
 type AWSLoadBalancerDeploymentConfig struct {
 	// replicas is the desired number of the controller replicas.
@@ -168,6 +628,50 @@ type AWSLoadBalancerDeploymentConfig struct {
 	// +kubebuilder:validation:Optional
 	// +optional
 	Replicas int32 `json:"replicas,omitempty"`
+
+	// nodeSelector is the node selector applied to the controller pods.
+	// The controller pods are scheduled only on the nodes which match all the given labels.
+	// For more info see https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#nodeselector.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// tolerations are the tolerations applied to the controller pods.
+	// They allow the controller pods to be scheduled on the nodes with the matching taints,
+	// for instance the infra nodes.
+	// For more info see https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration/.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	// +listType=atomic
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// affinity is the scheduling affinity applied to the controller pods.
+	// For more info see https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#affinity-and-anti-affinity.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// topologySpreadConstraints describes how the controller pods ought to spread
+	// across the topology domains like availability zones.
+	// For more info see https://kubernetes.io/docs/concepts/scheduling-eviction/topology-spread-constraints/.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	// +listType=map
+	// +listMapKey=topologyKey
+	// +listMapKey=whenUnsatisfiable
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// resources are the compute resource requirements of the controller container.
+	// When omitted, the operator requests 50m of CPU and 128Mi of memory and sets no limits.
+	// For more info see https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // AWSLoadBalancerCredentialsRequestConfig defines customization options for the controller's CredentialsRequest.
@@ -22,10 +22,26 @@ package v1
 
 import (
 	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSLoadBalancerAttribute) DeepCopyInto(out *AWSLoadBalancerAttribute) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLoadBalancerAttribute.
+func (in *AWSLoadBalancerAttribute) DeepCopy() *AWSLoadBalancerAttribute {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSLoadBalancerAttribute)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSLoadBalancerController) DeepCopyInto(out *AWSLoadBalancerController) {
 	*out = *in
@@ -53,6 +69,21 @@ func (in *AWSLoadBalancerController) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSLoadBalancerControllerAlerts) DeepCopyInto(out *AWSLoadBalancerControllerAlerts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLoadBalancerControllerAlerts.
+func (in *AWSLoadBalancerControllerAlerts) DeepCopy() *AWSLoadBalancerControllerAlerts {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSLoadBalancerControllerAlerts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSLoadBalancerControllerList) DeepCopyInto(out *AWSLoadBalancerControllerList) {
 	*out = *in
@@ -88,15 +119,25 @@ func (in *AWSLoadBalancerControllerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSLoadBalancerControllerSpec) DeepCopyInto(out *AWSLoadBalancerControllerSpec) {
 	*out = *in
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = new(AWSLoadBalancerControllerSubnets)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AdditionalResourceTags != nil {
 		in, out := &in.AdditionalResourceTags, &out.AdditionalResourceTags
 		*out = make([]AWSResourceTag, len(*in))
 		copy(*out, *in)
 	}
+	if in.IngressClassParams != nil {
+		in, out := &in.IngressClassParams, &out.IngressClassParams
+		*out = new(AWSLoadBalancerIngressClassParams)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
 		*out = new(AWSLoadBalancerDeploymentConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.EnabledAddons != nil {
 		in, out := &in.EnabledAddons, &out.EnabledAddons
@@ -113,6 +154,21 @@ func (in *AWSLoadBalancerControllerSpec) DeepCopyInto(out *AWSLoadBalancerContro
 		*out = new(AWSLoadBalancerCredentialsRequestConfig)
 		**out = **in
 	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make([]AWSLoadBalancerFeatureGate, len(*in))
+		copy(*out, *in)
+	}
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = new(AWSLoadBalancerControllerWebhooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(AWSLoadBalancerControllerAlerts)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLoadBalancerControllerSpec.
@@ -187,6 +243,66 @@ func (in *AWSLoadBalancerControllerStatusSubnets) DeepCopy() *AWSLoadBalancerCon
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSLoadBalancerControllerSubnets) DeepCopyInto(out *AWSLoadBalancerControllerSubnets) {
+	*out = *in
+	if in.Public != nil {
+		in, out := &in.Public, &out.Public
+		*out = new(AWSSubnetSelection)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Internal != nil {
+		in, out := &in.Internal, &out.Internal
+		*out = new(AWSSubnetSelection)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLoadBalancerControllerSubnets.
+func (in *AWSLoadBalancerControllerSubnets) DeepCopy() *AWSLoadBalancerControllerSubnets {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSLoadBalancerControllerSubnets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSLoadBalancerControllerWebhooks) DeepCopyInto(out *AWSLoadBalancerControllerWebhooks) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(AWSLoadBalancerWebhookConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetGroupBinding != nil {
+		in, out := &in.TargetGroupBinding, &out.TargetGroupBinding
+		*out = new(AWSLoadBalancerWebhookConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(AWSLoadBalancerWebhookConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(AWSLoadBalancerWebhookConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLoadBalancerControllerWebhooks.
+func (in *AWSLoadBalancerControllerWebhooks) DeepCopy() *AWSLoadBalancerControllerWebhooks {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSLoadBalancerControllerWebhooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSLoadBalancerCredentialsRequestConfig) DeepCopyInto(out *AWSLoadBalancerCredentialsRequestConfig) {
 	*out = *in
@@ -205,6 +321,37 @@ func (in *AWSLoadBalancerCredentialsRequestConfig) DeepCopy() *AWSLoadBalancerCr
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSLoadBalancerDeploymentConfig) DeepCopyInto(out *AWSLoadBalancerDeploymentConfig) {
 	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLoadBalancerDeploymentConfig.
@@ -217,6 +364,76 @@ func (in *AWSLoadBalancerDeploymentConfig) DeepCopy() *AWSLoadBalancerDeployment
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSLoadBalancerFeatureGate) DeepCopyInto(out *AWSLoadBalancerFeatureGate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLoadBalancerFeatureGate.
+func (in *AWSLoadBalancerFeatureGate) DeepCopy() *AWSLoadBalancerFeatureGate {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSLoadBalancerFeatureGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSLoadBalancerIngressClassParams) DeepCopyInto(out *AWSLoadBalancerIngressClassParams) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]AWSResourceTag, len(*in))
+		copy(*out, *in)
+	}
+	if in.LoadBalancerAttributes != nil {
+		in, out := &in.LoadBalancerAttributes, &out.LoadBalancerAttributes
+		*out = make([]AWSLoadBalancerAttribute, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLoadBalancerIngressClassParams.
+func (in *AWSLoadBalancerIngressClassParams) DeepCopy() *AWSLoadBalancerIngressClassParams {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSLoadBalancerIngressClassParams)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSLoadBalancerWebhookConfig) DeepCopyInto(out *AWSLoadBalancerWebhookConfig) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObjectSelector != nil {
+		in, out := &in.ObjectSelector, &out.ObjectSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLoadBalancerWebhookConfig.
+func (in *AWSLoadBalancerWebhookConfig) DeepCopy() *AWSLoadBalancerWebhookConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSLoadBalancerWebhookConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSResourceTag) DeepCopyInto(out *AWSResourceTag) {
 	*out = *in
@@ -231,3 +448,43 @@ func (in *AWSResourceTag) DeepCopy() *AWSResourceTag {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSSubnetSelection) DeepCopyInto(out *AWSSubnetSelection) {
+	*out = *in
+	if in.IDs != nil {
+		in, out := &in.IDs, &out.IDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]AWSSubnetTagSelector, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSSubnetSelection.
+func (in *AWSSubnetSelection) DeepCopy() *AWSSubnetSelection {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSSubnetSelection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSSubnetTagSelector) DeepCopyInto(out *AWSSubnetTagSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSSubnetTagSelector.
+func (in *AWSSubnetTagSelector) DeepCopy() *AWSSubnetTagSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSSubnetTagSelector)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -54,6 +54,9 @@ func (src *AWSLoadBalancerController) ConvertTo(dstRaw conversion.Hub) error {
 			Name: src.Spec.Credentials.Name,
 		}
 	}
+	for _, fg := range src.Spec.FeatureGates {
+		dst.Spec.FeatureGates = append(dst.Spec.FeatureGates, v1.AWSLoadBalancerFeatureGate{Name: v1.FeatureGateName(fg.Name), Enabled: fg.Enabled})
+	}
 
 	// Status
 	dst.Status.Conditions = src.Status.Conditions
@@ -103,6 +106,9 @@ func (dst *AWSLoadBalancerController) ConvertFrom(srcRaw conversion.Hub) error {
 			Name: src.Spec.Credentials.Name,
 		}
 	}
+	for _, fg := range src.Spec.FeatureGates {
+		dst.Spec.FeatureGates = append(dst.Spec.FeatureGates, AWSLoadBalancerFeatureGate{Name: FeatureGateName(fg.Name), Enabled: fg.Enabled})
+	}
 
 	// Status
 	dst.Status.Conditions = src.Status.Conditions
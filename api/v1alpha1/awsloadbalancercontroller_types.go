@@ -41,6 +41,10 @@ const (
 	ManualSubnetTaggingPolicy SubnetTaggingPolicy = "Manual"
 )
 
+// FeatureGateName is the name of a feature gate of the controller.
+// +kubebuilder:validation:Enum=EnableIPTargetType;EnableRGTAPI;ListenerRulesTagging;WeightedTargetGroups;SubnetsClusterTagCheck;EndpointsFailOpen
+type FeatureGateName string
+
 // AWSLoadBalancerControllerSpec defines the desired state of AWSLoadBalancerController
 type AWSLoadBalancerControllerSpec struct {
 
@@ -98,6 +102,29 @@ type AWSLoadBalancerControllerSpec struct {
 	// +kubebuilder:validation:Optional
 	// +optional
 	Credentials *SecretReference `json:"credentials,omitempty"`
+
+	// FeatureGates is the list of the controller's feature gates to be enabled or disabled.
+	//
+	// +kubebuilder:validation:Optional
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	FeatureGates []AWSLoadBalancerFeatureGate `json:"featureGates,omitempty"`
+}
+
+// AWSLoadBalancerFeatureGate enables or disables a feature gate of the controller.
+type AWSLoadBalancerFeatureGate struct {
+	// Name is the name of the feature gate.
+	//
+	// +kubebuilder:validation:Required
+	// +required
+	Name FeatureGateName `json:"name"`
+
+	// Enabled indicates whether the feature gate is enabled.
+	//
+	// +kubebuilder:validation:Required
+	// +required
+	Enabled bool `json:"enabled"`
 }
 
 type AWSLoadBalancerDeploymentConfig struct {
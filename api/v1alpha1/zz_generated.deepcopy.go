@@ -109,6 +109,11 @@ func (in *AWSLoadBalancerControllerSpec) DeepCopyInto(out *AWSLoadBalancerContro
 		*out = new(SecretReference)
 		**out = **in
 	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make([]AWSLoadBalancerFeatureGate, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLoadBalancerControllerSpec.
@@ -198,6 +203,21 @@ func (in *AWSLoadBalancerDeploymentConfig) DeepCopy() *AWSLoadBalancerDeployment
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSLoadBalancerFeatureGate) DeepCopyInto(out *AWSLoadBalancerFeatureGate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSLoadBalancerFeatureGate.
+func (in *AWSLoadBalancerFeatureGate) DeepCopy() *AWSLoadBalancerFeatureGate {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSLoadBalancerFeatureGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretReference) DeepCopyInto(out *SecretReference) {
 	*out = *in
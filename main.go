@@ -40,6 +40,7 @@ import (
 	cco "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
 	libgocrypto "github.com/openshift/library-go/pkg/crypto"
 
+	elbv1beta1 "sigs.k8s.io/aws-load-balancer-controller/apis/elbv2/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -57,15 +58,6 @@ import (
 	//+kubebuilder:scaffold:imports
 )
 
-const (
-	clusterInfrastructureName = "cluster"
-	// It's been noticed that the freshly provisioned credentials
-	// may not be usable immediately. Therefore the first AWS call needs to be
-	// repeated until it succeeds or times out.
-	awsRequestTimeout      = 20 * time.Second
-	awsRequestPollInterval = 1 * time.Second
-)
-
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
@@ -85,6 +77,7 @@ func init() {
 	utilruntime.Must(cco.Install(scheme))
 	utilruntime.Must(networkingv1.AddToScheme(scheme))
 	utilruntime.Must(arv1.AddToScheme(scheme))
+	utilruntime.Must(elbv1beta1.AddToScheme(scheme))
 }
 
 func main() {
@@ -96,6 +89,9 @@ func main() {
 		image                  string
 		trustedCAConfigMapName string
 		webhookDisableHTTP2    bool
+		subnetResyncInterval   time.Duration
+		clusterInfoRefresh     time.Duration
+		awsRetryOptions        = aws.DefaultRetryOptions
 	)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8443", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -106,6 +102,11 @@ func main() {
 	flag.StringVar(&image, "image", "quay.io/aws-load-balancer-operator/aws-load-balancer-controller:latest", "The image to be used for the operand")
 	flag.StringVar(&trustedCAConfigMapName, "trusted-ca-configmap", "", "The name of the config map containing TLS CA(s) which should be trusted by the controller's containers. PEM encoded file under \"ca-bundle.crt\" key is expected.")
 	flag.BoolVar(&webhookDisableHTTP2, "webhook-disable-http2", false, "Disable HTTP/2 for the webhook server.")
+	flag.DurationVar(&subnetResyncInterval, "subnet-resync-interval", 10*time.Minute, "The interval at which the cluster subnets are re-discovered and re-tagged. Set to 0 to disable the periodic resync.")
+	flag.DurationVar(&clusterInfoRefresh, "cluster-info-refresh-interval", time.Hour, "The interval at which the cluster name, AWS region and VPC ID of the cluster are resolved again. Set to 0 to disable the periodic refresh.")
+	flag.IntVar(&awsRetryOptions.MaxAttempts, "aws-max-attempts", awsRetryOptions.MaxAttempts, "The maximum number of attempts of a failed AWS API request, including the first one.")
+	flag.DurationVar(&awsRetryOptions.MaxBackoff, "aws-max-backoff", awsRetryOptions.MaxBackoff, "The maximum delay between two attempts of a failed AWS API request.")
+	flag.BoolVar(&awsRetryOptions.AdaptiveRateLimiting, "aws-adaptive-rate-limiting", awsRetryOptions.AdaptiveRateLimiting, "Rate limit the AWS API requests on the client side when they get throttled.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -170,13 +171,6 @@ func main() {
 		os.Exit(1)
 	}
 
-	// get the cluster details
-	clusterName, awsRegion, err := clusterInfo(context.TODO(), mgr.GetClient())
-	if err != nil {
-		setupLog.Error(err, "failed to get cluster details")
-		os.Exit(1)
-	}
-
 	// self provision with AWS credentials
 	setupLog.Info("provisioning credentials")
 	awsSharedCredFileName, err := operator.ProvisionCredentials(context.TODO(), mgr.GetClient(), namespace)
@@ -185,30 +179,25 @@ func main() {
 		os.Exit(1)
 	}
 
-	// make and aws.EC2Client
-	ec2Client, err := aws.NewClient(context.TODO(), awsRegion, awsSharedCredFileName)
-	if err != nil {
-		setupLog.Error(err, "failed to make aws client")
-		os.Exit(1)
-	}
-
-	// get the VPC ID where the cluster is running
-	vpcID, err := getVPCId(context.TODO(), ec2Client, clusterName, awsRequestTimeout, awsRequestPollInterval)
-	if err != nil {
-		setupLog.Error(err, "failed to get VPC ID")
-		os.Exit(1)
-	}
+	// the retryer is shared by the EC2 clients to rate limit all the requests of the operator
+	awsRetryer := aws.NewRetryer(awsRetryOptions)
 
 	if err = (&awsloadbalancercontroller.AWSLoadBalancerControllerReconciler{
 		Client:                 mgr.GetClient(),
 		Scheme:                 mgr.GetScheme(),
-		EC2Client:              ec2Client,
 		Namespace:              namespace,
 		Image:                  image,
-		VPCID:                  vpcID,
-		ClusterName:            clusterName,
-		AWSRegion:              awsRegion,
 		TrustedCAConfigMapName: trustedCAConfigMapName,
+		// the cluster name, the AWS region and the VPC ID are resolved by the reconciliation
+		// so that a transient AWS failure doesn't prevent the operator from starting
+		NewEC2Client: func(ctx context.Context, region string) (aws.EC2Client, error) {
+			return aws.NewClient(ctx, region, awsSharedCredFileName, awsRetryer)
+		},
+		ClusterInfoRefreshInterval: clusterInfoRefresh,
+		SubnetResyncInterval:       subnetResyncInterval,
+		Recorder:                   mgr.GetEventRecorderFor("aws-load-balancer-operator"),
+		// the Ingresses and Services blocking the deletion can be in any namespace
+		APIReader: mgr.GetAPIReader(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AWSLoadBalancerController")
 		os.Exit(1)
@@ -235,51 +224,6 @@ func main() {
 	}
 }
 
-func clusterInfo(ctx context.Context, client client.Client) (clusterName, awsRegion string, err error) {
-	var infra configv1.Infrastructure
-	infraKey := types.NamespacedName{
-		Name: clusterInfrastructureName,
-	}
-	err = client.Get(ctx, infraKey, &infra)
-	if err != nil {
-		err = fmt.Errorf("failed to get Infrastructure %q: %w", clusterInfrastructureName, err)
-		return
-	}
-
-	if infra.Status.InfrastructureName == "" {
-		err = fmt.Errorf("could not get AWS region from Infrastructure %q status", clusterInfrastructureName)
-		return
-	}
-	clusterName = infra.Status.InfrastructureName
-
-	if infra.Status.PlatformStatus == nil || infra.Status.PlatformStatus.AWS == nil || infra.Status.PlatformStatus.AWS.Region == "" {
-		err = fmt.Errorf("could not get AWS region from Infrastructure %q status", clusterInfrastructureName)
-		return
-	}
-	awsRegion = infra.Status.PlatformStatus.AWS.Region
-	return
-}
-
-// getVPCId tries to retrieve VPC ID of the given cluster polling until it succeeds or times out.
-func getVPCId(ctx context.Context, ec2Client aws.EC2Client, clusterName string, timeout, pollInterval time.Duration) (string, error) {
-	timeoutCh := time.After(timeout)
-	ticker := time.NewTicker(pollInterval)
-
-	for {
-		select {
-		case <-timeoutCh:
-			return "", fmt.Errorf("timed out trying to get vpc id")
-		case <-ticker.C:
-			vpcID, err := aws.GetVPCId(ctx, ec2Client, clusterName)
-			if err != nil {
-				setupLog.Info("failed to get VPC ID", "error", err)
-				continue
-			}
-			return vpcID, nil
-		}
-	}
-}
-
 // tlsGroupToCurveID maps a configv1.TLSGroup to a crypto/tls CurveID.
 // Groups not supported by the Go runtime are returned with ok=false.
 var tlsGroupToCurveID = map[configv1.TLSGroup]tls.CurveID{
@@ -8,21 +8,27 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 const (
-	clusterTagKey    = "kubernetes.io/cluster/%s"
-	tagKeyFilterName = "tag-key"
+	clusterTagKey           = "kubernetes.io/cluster/%s"
+	controlPlaneNamePattern = "%s-master-*"
+	tagKeyFilterName        = "tag-key"
+	tagNameFilterName       = "tag:Name"
+	instanceStateFilterName = "instance-state-name"
 )
 
-// VPCClient can be used to query VPCs
+// VPCClient can be used to query VPCs and the instances from which the VPC of the cluster can be derived
 type VPCClient interface {
 	DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error)
+	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
 }
 
-// SubnetClient can be used to query subnets and perform tagging operations
+// SubnetClient can be used to query subnets with their route tables and perform tagging operations
 type SubnetClient interface {
 	DescribeSubnets(context.Context, *ec2.DescribeSubnetsInput, ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+	DescribeRouteTables(context.Context, *ec2.DescribeRouteTablesInput, ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error)
 	CreateTags(context.Context, *ec2.CreateTagsInput, ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error)
 	DeleteTags(context.Context, *ec2.DeleteTagsInput, ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error)
 }
@@ -33,17 +39,69 @@ type EC2Client interface {
 	SubnetClient
 }
 
-func NewClient(ctx context.Context, awsRegion, sharedCredFileName string) (EC2Client, error) {
-	awsConfig, err := config.LoadDefaultConfig(ctx, config.WithRegion(awsRegion), config.WithSharedCredentialsFiles([]string{sharedCredFileName}))
+// NewClient returns an EC2Client for the given region which retries the failed requests with the given retryer.
+// The retries and the throttled attempts of the requests are counted in the metrics.
+func NewClient(ctx context.Context, awsRegion, sharedCredFileName string, retryer aws.Retryer) (EC2Client, error) {
+	awsConfig, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(awsRegion),
+		config.WithSharedCredentialsFiles([]string{sharedCredFileName}),
+		config.WithRetryer(func() aws.Retryer { return retryer }),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load AWS config: %w", err)
 	}
-	return ec2.NewFromConfig(awsConfig), nil
+	return NewInstrumentedClient(ec2.NewFromConfig(awsConfig, func(o *ec2.Options) {
+		o.APIOptions = append(o.APIOptions, addAttemptMetricsMiddleware)
+	})), nil
 }
 
-// GetVPCId return the VPC ID of the cluster
-func GetVPCId(ctx context.Context, ec2Client EC2Client, clusterName string) (string, error) {
+// VPCSource identifies the AWS resources from which the VPC of the cluster was discovered.
+type VPCSource string
+
+const (
+	// VPCSourceVPCTag means that the VPC is tagged with the cluster tag.
+	VPCSourceVPCTag VPCSource = "VPCTag"
+	// VPCSourceSubnetTags means that the VPC contains the subnets tagged with the cluster tag.
+	VPCSourceSubnetTags VPCSource = "SubnetTags"
+	// VPCSourceControlPlaneInstances means that the VPC contains the control plane instances of the cluster.
+	VPCSourceControlPlaneInstances VPCSource = "ControlPlaneInstances"
+)
+
+// GetVPCId returns the VPC ID of the cluster and the source from which it was discovered.
+// The VPC tagged with the cluster tag is used first. If no VPC is tagged, which is common
+// for the clusters installed into an existing VPC, the VPC is derived from the subnets
+// tagged with the cluster tag and then from the control plane instances of the cluster.
+func GetVPCId(ctx context.Context, ec2Client EC2Client, clusterName string) (string, VPCSource, error) {
 	infraTagKey := fmt.Sprintf(clusterTagKey, clusterName)
+
+	vpcID, err := getTaggedVPCId(ctx, ec2Client, infraTagKey)
+	if err != nil {
+		return "", "", err
+	}
+	if vpcID != "" {
+		return vpcID, VPCSourceVPCTag, nil
+	}
+
+	vpcID, err = getSubnetsVPCId(ctx, ec2Client, infraTagKey)
+	if err != nil {
+		return "", "", err
+	}
+	if vpcID != "" {
+		return vpcID, VPCSourceSubnetTags, nil
+	}
+
+	vpcID, err = getControlPlaneVPCId(ctx, ec2Client, clusterName, infraTagKey)
+	if err != nil {
+		return "", "", err
+	}
+	if vpcID != "" {
+		return vpcID, VPCSourceControlPlaneInstances, nil
+	}
+	return "", "", fmt.Errorf("no VPC, subnets or control plane instances with tag %q found", infraTagKey)
+}
+
+// getTaggedVPCId returns the ID of the VPC with the given tag key, or an empty string if no VPC is tagged.
+func getTaggedVPCId(ctx context.Context, ec2Client EC2Client, infraTagKey string) (string, error) {
 	vpcs, err := ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
 		Filters: []ec2types.Filter{
 			{
@@ -55,11 +113,86 @@ func GetVPCId(ctx context.Context, ec2Client EC2Client, clusterName string) (str
 	if err != nil {
 		return "", fmt.Errorf("failed to list VPC with tag %q: %w", infraTagKey, err)
 	}
-	if len(vpcs.Vpcs) == 0 {
-		return "", fmt.Errorf("no VPC with tag %q found", infraTagKey)
-	}
 	if len(vpcs.Vpcs) > 1 {
 		return "", fmt.Errorf("multiple VPCs with tag %q found", infraTagKey)
 	}
+	if len(vpcs.Vpcs) == 0 {
+		return "", nil
+	}
 	return aws.ToString(vpcs.Vpcs[0].VpcId), nil
 }
+
+// getSubnetsVPCId returns the ID of the VPC of the subnets with the given tag key,
+// or an empty string if no subnet is tagged.
+func getSubnetsVPCId(ctx context.Context, ec2Client EC2Client, infraTagKey string) (string, error) {
+	subnetsPaginator := ec2.NewDescribeSubnetsPaginator(ec2Client, &ec2.DescribeSubnetsInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String(tagKeyFilterName),
+				Values: []string{infraTagKey},
+			},
+		},
+	})
+
+	vpcIDs := sets.New[string]()
+	for subnetsPaginator.HasMorePages() {
+		response, err := subnetsPaginator.NextPage(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to list subnets with tag %q: %w", infraTagKey, err)
+		}
+		for _, s := range response.Subnets {
+			vpcIDs.Insert(aws.ToString(s.VpcId))
+		}
+	}
+	return singleVPCId(vpcIDs, "subnets", infraTagKey)
+}
+
+// getControlPlaneVPCId returns the ID of the VPC of the control plane instances of the cluster,
+// or an empty string if no control plane instance is found. The control plane instances
+// are the instances with the given tag key which are named after the master machines of the cluster.
+func getControlPlaneVPCId(ctx context.Context, ec2Client EC2Client, clusterName, infraTagKey string) (string, error) {
+	instancesPaginator := ec2.NewDescribeInstancesPaginator(ec2Client, &ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String(tagKeyFilterName),
+				Values: []string{infraTagKey},
+			},
+			{
+				Name:   aws.String(tagNameFilterName),
+				Values: []string{fmt.Sprintf(controlPlaneNamePattern, clusterName)},
+			},
+			{
+				// the terminated instances are not attached to a VPC anymore
+				Name:   aws.String(instanceStateFilterName),
+				Values: []string{"pending", "running", "stopping", "stopped"},
+			},
+		},
+	})
+
+	vpcIDs := sets.New[string]()
+	for instancesPaginator.HasMorePages() {
+		response, err := instancesPaginator.NextPage(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to list control plane instances with tag %q: %w", infraTagKey, err)
+		}
+		for _, r := range response.Reservations {
+			for _, i := range r.Instances {
+				vpcIDs.Insert(aws.ToString(i.VpcId))
+			}
+		}
+	}
+	return singleVPCId(vpcIDs, "control plane instances", infraTagKey)
+}
+
+// singleVPCId returns the only VPC ID of the given set, an empty string if the set is empty
+// or an error if the resources with the given tag key belong to multiple VPCs.
+func singleVPCId(vpcIDs sets.Set[string], resources, infraTagKey string) (string, error) {
+	vpcIDs.Delete("")
+	if vpcIDs.Len() > 1 {
+		return "", fmt.Errorf("%s with tag %q found in multiple VPCs: %v", resources, infraTagKey, sets.List(vpcIDs))
+	}
+	if vpcIDs.Len() == 0 {
+		return "", nil
+	}
+	return sets.List(vpcIDs)[0], nil
+}
@@ -10,33 +10,46 @@ import (
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 )
 
+// testEC2Client returns the VPCs, subnets and instances tagged for the cluster.
+// Only the queries made by GetVPCId are supported.
 type testEC2Client struct {
 	SubnetClient
 	t           *testing.T
 	clusterName string
-	output      []string
+	vpcs        []string
+	subnets     []ec2types.Subnet
+	instances   []ec2types.Instance
+	calls       []string
 }
 
-func (c *testEC2Client) DescribeVpcs(ctx context.Context, input *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+// checkClusterTagFilter verifies that the first filter selects the resources by the cluster tag key.
+func (c *testEC2Client) checkClusterTagFilter(filters []ec2types.Filter) {
 	c.t.Helper()
-	if len(input.Filters) != 1 {
+	if len(filters) == 0 {
 		c.t.Fatalf("unexpected input filters")
 	}
-	if aws.ToString(input.Filters[0].Name) != "tag-key" {
-		c.t.Errorf("unexpected filter name, expected %q, got %q", "tag-key", aws.ToString(input.Filters[0].Name))
+	if aws.ToString(filters[0].Name) != "tag-key" {
+		c.t.Errorf("unexpected filter name, expected %q, got %q", "tag-key", aws.ToString(filters[0].Name))
 	}
-
-	if len(input.Filters[0].Values) != 1 {
+	if len(filters[0].Values) != 1 {
 		c.t.Errorf("unexpected number of filter values")
 	}
+	if !strings.Contains(filters[0].Values[0], c.clusterName) {
+		c.t.Errorf("filter value %s does not contain %s", filters[0].Values[0], c.clusterName)
+	}
+}
 
-	if !strings.Contains(input.Filters[0].Values[0], c.clusterName) {
-		c.t.Errorf("filter value %s does not contain %s", input.Filters[0].Values[0], c.clusterName)
+func (c *testEC2Client) DescribeVpcs(ctx context.Context, input *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	c.t.Helper()
+	c.calls = append(c.calls, "DescribeVpcs")
+	if len(input.Filters) != 1 {
+		c.t.Fatalf("unexpected input filters")
 	}
+	c.checkClusterTagFilter(input.Filters)
 	output := &ec2.DescribeVpcsOutput{
 		Vpcs: nil,
 	}
-	for _, i := range c.output {
+	for _, i := range c.vpcs {
 		output.Vpcs = append(output.Vpcs, ec2types.Vpc{
 			VpcId: aws.String(i),
 		})
@@ -44,57 +57,130 @@ func (c *testEC2Client) DescribeVpcs(ctx context.Context, input *ec2.DescribeVpc
 	return output, nil
 }
 
-func makeTestClient(t *testing.T, clusterName string, output []string) *testEC2Client {
-	return &testEC2Client{
-		t:           t,
-		clusterName: clusterName,
-		output:      output,
+func (c *testEC2Client) DescribeSubnets(ctx context.Context, input *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	c.t.Helper()
+	c.calls = append(c.calls, "DescribeSubnets")
+	c.checkClusterTagFilter(input.Filters)
+	return &ec2.DescribeSubnetsOutput{Subnets: c.subnets}, nil
+}
+
+func (c *testEC2Client) DescribeInstances(ctx context.Context, input *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	c.t.Helper()
+	c.calls = append(c.calls, "DescribeInstances")
+	c.checkClusterTagFilter(input.Filters)
+	if len(input.Filters) != 3 || aws.ToString(input.Filters[1].Name) != "tag:Name" || input.Filters[1].Values[0] != c.clusterName+"-master-*" {
+		c.t.Errorf("unexpected control plane instance filters %v", input.Filters)
 	}
+	return &ec2.DescribeInstancesOutput{Reservations: []ec2types.Reservation{{Instances: c.instances}}}, nil
+}
+
+func subnetInVPC(vpcID string) ec2types.Subnet {
+	return ec2types.Subnet{SubnetId: aws.String("subnet-" + vpcID), VpcId: aws.String(vpcID)}
+}
+
+func instanceInVPC(vpcID string) ec2types.Instance {
+	return ec2types.Instance{InstanceId: aws.String("i-" + vpcID), VpcId: aws.String(vpcID)}
 }
 
 func TestGetVPCId(t *testing.T) {
 	for _, tc := range []struct {
-		name          string
-		clusterName   string
-		expectedErr   string
-		vpcIDs        []string
-		expectedVPCID string
+		name              string
+		clusterName       string
+		vpcIDs            []string
+		subnets           []ec2types.Subnet
+		instances         []ec2types.Instance
+		expectedErr       string
+		expectedVPCID     string
+		expectedVPCSource VPCSource
+		expectedCalls     []string
 	}{
 		{
-			name:          "success case",
+			name:              "tagged vpc",
+			clusterName:       "test-cluster",
+			vpcIDs:            []string{"test-vpc"},
+			subnets:           []ec2types.Subnet{subnetInVPC("subnet-vpc")},
+			instances:         []ec2types.Instance{instanceInVPC("instance-vpc")},
+			expectedVPCID:     "test-vpc",
+			expectedVPCSource: VPCSourceVPCTag,
+			expectedCalls:     []string{"DescribeVpcs"},
+		},
+		{
+			name:              "vpc of the tagged subnets",
+			clusterName:       "test-cluster",
+			subnets:           []ec2types.Subnet{subnetInVPC("subnet-vpc"), subnetInVPC("subnet-vpc")},
+			instances:         []ec2types.Instance{instanceInVPC("instance-vpc")},
+			expectedVPCID:     "subnet-vpc",
+			expectedVPCSource: VPCSourceSubnetTags,
+			expectedCalls:     []string{"DescribeVpcs", "DescribeSubnets"},
+		},
+		{
+			name:              "vpc of the control plane instances",
+			clusterName:       "test-cluster",
+			instances:         []ec2types.Instance{instanceInVPC("instance-vpc"), {InstanceId: aws.String("i-detached")}},
+			expectedVPCID:     "instance-vpc",
+			expectedVPCSource: VPCSourceControlPlaneInstances,
+			expectedCalls:     []string{"DescribeVpcs", "DescribeSubnets", "DescribeInstances"},
+		},
+		{
+			name:          "no matching vpc",
 			clusterName:   "test-cluster",
-			vpcIDs:        []string{"test-vpc"},
-			expectedVPCID: "test-vpc",
+			expectedErr:   `no VPC, subnets or control plane instances with tag "kubernetes.io/cluster/test-cluster" found`,
+			expectedCalls: []string{"DescribeVpcs", "DescribeSubnets", "DescribeInstances"},
 		},
 		{
-			name:        "no matching vpc",
-			clusterName: "test-cluster",
-			expectedErr: `no VPC with tag "kubernetes.io/cluster/test-cluster" found`,
+			name:          "multiple matching vpc",
+			clusterName:   "test-cluster",
+			expectedErr:   `multiple VPCs with tag "kubernetes.io/cluster/test-cluster" found`,
+			vpcIDs:        []string{"test-vpc-1", "test-vpc-2"},
+			subnets:       []ec2types.Subnet{subnetInVPC("subnet-vpc")},
+			expectedCalls: []string{"DescribeVpcs"},
 		},
 		{
-			name:        "multiple matching vpc",
-			clusterName: "test-cluster",
-			expectedErr: `multiple VPCs with tag "kubernetes.io/cluster/test-cluster" found`,
-			vpcIDs:      []string{"test-vpc-1", "test-vpc-2"},
+			name:          "tagged subnets in multiple vpcs",
+			clusterName:   "test-cluster",
+			subnets:       []ec2types.Subnet{subnetInVPC("vpc-2"), subnetInVPC("vpc-1")},
+			instances:     []ec2types.Instance{instanceInVPC("instance-vpc")},
+			expectedErr:   `subnets with tag "kubernetes.io/cluster/test-cluster" found in multiple VPCs: [vpc-1 vpc-2]`,
+			expectedCalls: []string{"DescribeVpcs", "DescribeSubnets"},
+		},
+		{
+			name:          "control plane instances in multiple vpcs",
+			clusterName:   "test-cluster",
+			instances:     []ec2types.Instance{instanceInVPC("vpc-1"), instanceInVPC("vpc-2")},
+			expectedErr:   `control plane instances with tag "kubernetes.io/cluster/test-cluster" found in multiple VPCs: [vpc-1 vpc-2]`,
+			expectedCalls: []string{"DescribeVpcs", "DescribeSubnets", "DescribeInstances"},
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			vpcID, err := GetVPCId(context.Background(), makeTestClient(t, tc.clusterName, tc.vpcIDs), tc.clusterName)
+			client := &testEC2Client{
+				t:           t,
+				clusterName: tc.clusterName,
+				vpcs:        tc.vpcIDs,
+				subnets:     tc.subnets,
+				instances:   tc.instances,
+			}
+			vpcID, vpcSource, err := GetVPCId(context.Background(), client, tc.clusterName)
+			if strings.Join(client.calls, ",") != strings.Join(tc.expectedCalls, ",") {
+				t.Errorf("expected calls %v, got %v", tc.expectedCalls, client.calls)
+			}
 			if tc.expectedErr != "" {
 				if err == nil {
-					t.Errorf("expected error %s, got nil", tc.expectedErr)
+					t.Fatalf("expected error %s, got nil", tc.expectedErr)
 				}
 				if !strings.Contains(err.Error(), tc.expectedErr) {
 					t.Errorf("expected error to contain %q, instead error is %q", tc.expectedErr, err)
 				}
 				return
 			}
-			if tc.expectedErr == "" && err != nil {
+			if err != nil {
 				t.Fatalf("unexpected error %v", err)
 			}
 			if vpcID != tc.expectedVPCID {
 				t.Errorf("expected VPC Id %q, got %q", tc.expectedVPCID, vpcID)
 			}
+			if vpcSource != tc.expectedVPCSource {
+				t.Errorf("expected VPC source %q, got %q", tc.expectedVPCSource, vpcSource)
+			}
 		})
 	}
 }
@@ -0,0 +1,74 @@
+package aws
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// RequestError is returned by the EC2Client when an AWS API request fails.
+// The message of the wrapped error is kept, the error only classifies the failure
+// so that the callers can tell the transient failures from the permanent ones.
+type RequestError struct {
+	// Operation is the name of the failed AWS API operation.
+	Operation string
+	// Code is the error code returned by the AWS API.
+	Code string
+	// Throttled is true if the request was throttled by the AWS API or by the client side rate limiting.
+	Throttled bool
+	// Transient is true if the request may succeed when retried later.
+	Transient bool
+	// Err is the error returned by the AWS SDK.
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// newRequestError classifies the error returned for the given operation.
+// The request is considered as throttled if the last attempt was throttled or if the retry quota
+// of the client was exhausted, the latter happens when many requests are retried at the same time.
+func newRequestError(operation string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var quotaErr ratelimit.QuotaExceededError
+	throttled := isThrottleError(err) || errors.As(err, &quotaErr)
+	return &RequestError{
+		Operation: operation,
+		Code:      errorCode(err),
+		Throttled: throttled,
+		Transient: throttled || isRetryableError(err),
+		Err:       err,
+	}
+}
+
+// IsThrottled checks whether the given error was caused by the throttling of an AWS API request.
+func IsThrottled(err error) bool {
+	var reqErr *RequestError
+	return errors.As(err, &reqErr) && reqErr.Throttled
+}
+
+// IsTransient checks whether the given error was caused by an AWS API request which may succeed later,
+// like a throttled request or a request which failed because of a network or a server error.
+func IsTransient(err error) bool {
+	var reqErr *RequestError
+	return errors.As(err, &reqErr) && reqErr.Transient
+}
+
+// isThrottleError checks whether the error code is one of the throttling error codes of the AWS API.
+func isThrottleError(err error) bool {
+	return retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary
+}
+
+// isRetryableError checks whether the error is one the AWS SDK retries, like the timeouts or the server errors.
+func isRetryableError(err error) bool {
+	return retry.IsErrorRetryables(retry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary
+}
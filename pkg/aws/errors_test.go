@@ -0,0 +1,97 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go"
+)
+
+func TestRequestError(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		err               error
+		expectedCode      string
+		expectedThrottled bool
+		expectedTransient bool
+	}{
+		{
+			name:              "request limit exceeded",
+			err:               &smithy.GenericAPIError{Code: "RequestLimitExceeded", Message: "Request limit exceeded."},
+			expectedCode:      "RequestLimitExceeded",
+			expectedThrottled: true,
+			expectedTransient: true,
+		},
+		{
+			name:              "max attempts reached while throttled",
+			err:               &retry.MaxAttemptsError{Attempt: 5, Err: &smithy.GenericAPIError{Code: "Throttling"}},
+			expectedCode:      "Throttling",
+			expectedThrottled: true,
+			expectedTransient: true,
+		},
+		{
+			name:              "retry quota exhausted",
+			err:               fmt.Errorf("failed to get rate limit token, retry quota exceeded, %w", ratelimit.QuotaExceededError{Available: 0, Requested: 5}),
+			expectedCode:      unknownErrorCode,
+			expectedThrottled: true,
+			expectedTransient: true,
+		},
+		{
+			name:              "request timeout",
+			err:               &smithy.GenericAPIError{Code: "RequestTimeout", Message: "Request timed out."},
+			expectedCode:      "RequestTimeout",
+			expectedTransient: true,
+		},
+		{
+			name:         "permission error",
+			err:          &smithy.GenericAPIError{Code: "UnauthorizedOperation", Message: "not authorized"},
+			expectedCode: "UnauthorizedOperation",
+		},
+		{
+			name:         "other error",
+			err:          errors.New("invalid input"),
+			expectedCode: unknownErrorCode,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewInstrumentedClient(&failingEC2Client{err: tc.err})
+			_, err := client.CreateTags(context.Background(), &ec2.CreateTagsInput{})
+			wrapped := fmt.Errorf("failed to tag subnets: %w", err)
+
+			var reqErr *RequestError
+			if !errors.As(wrapped, &reqErr) {
+				t.Fatalf("expected a RequestError, got %T", err)
+			}
+			if reqErr.Operation != "CreateTags" || reqErr.Code != tc.expectedCode {
+				t.Errorf("expected operation CreateTags and code %s, got operation %s and code %s", tc.expectedCode, reqErr.Operation, reqErr.Code)
+			}
+			if err.Error() != tc.err.Error() {
+				t.Errorf("expected the message of the SDK error %q, got %q", tc.err.Error(), err.Error())
+			}
+			if !errors.Is(wrapped, tc.err) {
+				t.Errorf("expected the error to wrap %v", tc.err)
+			}
+			if IsThrottled(wrapped) != tc.expectedThrottled {
+				t.Errorf("expected throttled to be %t", tc.expectedThrottled)
+			}
+			if IsTransient(wrapped) != tc.expectedTransient {
+				t.Errorf("expected transient to be %t", tc.expectedTransient)
+			}
+		})
+	}
+}
+
+func TestRequestErrorNil(t *testing.T) {
+	client := NewInstrumentedClient(&failingEC2Client{})
+	if _, err := client.DeleteTags(context.Background(), &ec2.DeleteTagsInput{}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if IsThrottled(nil) || IsTransient(nil) {
+		t.Errorf("expected nil error to be neither throttled nor transient")
+	}
+}
@@ -0,0 +1,356 @@
+// Package fake provides an in-memory implementation of the EC2 client used by the operator.
+// It's meant to run the subnet discovery and tagging flows in the tests without an AWS account.
+package fake
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+
+	albaws "github.com/openshift/aws-load-balancer-operator/pkg/aws"
+)
+
+const (
+	vpcIDFilterName         = "vpc-id"
+	subnetIDFilterName      = "subnet-id"
+	tagKeyFilterName        = "tag-key"
+	tagFilterNamePrefix     = "tag:"
+	instanceStateFilterName = "instance-state-name"
+)
+
+var _ albaws.EC2Client = &EC2{}
+
+// EC2 is an in-memory EC2 client which holds VPCs, subnets, route tables and instances with their tags.
+// The Describe operations support the ID and the filters used by the operator, and paginate the results.
+// The failures of the operations are scripted with faults, the eventual consistency of the tags
+// is simulated with a propagation delay. The client is safe for concurrent use.
+type EC2 struct {
+	// PageSize is the number of results returned by a Describe request which doesn't set MaxResults.
+	// All the results are returned at once if zero.
+	PageSize int
+
+	mu          sync.Mutex
+	vpcs        []ec2types.Vpc
+	subnets     []ec2types.Subnet
+	routeTables []ec2types.RouteTable
+	instances   []ec2types.Instance
+	// tags are the visible tags of the resources by resource ID
+	tags map[string]map[string]string
+	// pendingTags are the tag changes not visible yet
+	pendingTags      []tagChange
+	propagationDelay int
+	faults           []*Fault
+	// failedResources are the resources of the fault returned by the last request
+	failedResources []string
+	calls           map[string]int
+}
+
+// tagChange is a tag creation or removal which becomes visible after a number of Describe requests.
+type tagChange struct {
+	resourceID string
+	key        string
+	// value is nil for a removal
+	value     *string
+	remaining int
+}
+
+// Fault is a scripted failure of the EC2 operations.
+type Fault struct {
+	// Operation is the name of the failing operation, like "CreateTags".
+	// Every operation fails if empty.
+	Operation string
+	// Err is the error returned by the failing requests.
+	Err error
+	// Count is the number of requests which fail before the operation succeeds again.
+	// Every matching request fails if zero.
+	Count int
+	// ResourceIDs limits the fault to the CreateTags and DeleteTags requests for these resources.
+	// The other resources of a failing request are still tagged, which simulates a partial failure.
+	ResourceIDs []string
+}
+
+// NewEC2 returns an empty in-memory EC2 client.
+func NewEC2() *EC2 {
+	return &EC2{
+		tags:  make(map[string]map[string]string),
+		calls: make(map[string]int),
+	}
+}
+
+// ThrottlingError returns the error returned by the EC2 API when the request rate limit is exceeded.
+func ThrottlingError() error {
+	return &smithy.GenericAPIError{Code: "RequestLimitExceeded", Message: "Request limit exceeded.", Fault: smithy.FaultClient}
+}
+
+// AddVPC adds the VPC with the given tags.
+func (f *EC2) AddVPC(vpc ec2types.Vpc) *EC2 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.vpcs = append(f.vpcs, vpc)
+	f.setTags(aws.ToString(vpc.VpcId), vpc.Tags)
+	return f
+}
+
+// AddSubnet adds the subnet with the given tags.
+func (f *EC2) AddSubnet(subnet ec2types.Subnet) *EC2 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subnets = append(f.subnets, subnet)
+	f.setTags(aws.ToString(subnet.SubnetId), subnet.Tags)
+	return f
+}
+
+// AddRouteTable adds the route table with its associations and routes.
+func (f *EC2) AddRouteTable(routeTable ec2types.RouteTable) *EC2 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.routeTables = append(f.routeTables, routeTable)
+	f.setTags(aws.ToString(routeTable.RouteTableId), routeTable.Tags)
+	return f
+}
+
+// AddInstance adds the instance with the given tags.
+func (f *EC2) AddInstance(instance ec2types.Instance) *EC2 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.instances = append(f.instances, instance)
+	f.setTags(aws.ToString(instance.InstanceId), instance.Tags)
+	return f
+}
+
+// InjectFault adds the given fault. The faults are evaluated in the order they were added.
+func (f *EC2) InjectFault(fault Fault) *EC2 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = append(f.faults, &fault)
+	return f
+}
+
+// SetTagPropagationDelay hides the tag changes from the given number of Describe requests
+// following the CreateTags or DeleteTags request, like the eventual consistency of the EC2 API.
+func (f *EC2) SetTagPropagationDelay(requests int) *EC2 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.propagationDelay = requests
+	return f
+}
+
+// Tags returns the tags of the given resource, including the changes which are not visible yet.
+func (f *EC2) Tags(resourceID string) map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.currentTags(resourceID)
+}
+
+// Calls returns the number of requests made to the given operation, including the failed ones.
+func (f *EC2) Calls(operation string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[operation]
+}
+
+func (f *EC2) DescribeVpcs(_ context.Context, input *ec2.DescribeVpcsInput, _ ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("DescribeVpcs", nil); err != nil {
+		return nil, err
+	}
+	defer f.propagateTags()
+
+	var vpcs []ec2types.Vpc
+	for _, vpc := range f.vpcs {
+		id := aws.ToString(vpc.VpcId)
+		matches, err := f.matches(id, input.VpcIds, input.Filters, map[string]string{vpcIDFilterName: id})
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			vpc.Tags = f.visibleTags(id)
+			vpcs = append(vpcs, vpc)
+		}
+	}
+	if err := checkFound("InvalidVpcID.NotFound", "VPC", input.VpcIds, len(vpcs)); err != nil {
+		return nil, err
+	}
+	page, next, err := paginate(vpcs, input.MaxResults, input.NextToken, f.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeVpcsOutput{Vpcs: page, NextToken: next}, nil
+}
+
+func (f *EC2) DescribeSubnets(_ context.Context, input *ec2.DescribeSubnetsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("DescribeSubnets", nil); err != nil {
+		return nil, err
+	}
+	defer f.propagateTags()
+
+	var subnets []ec2types.Subnet
+	for _, subnet := range f.subnets {
+		id := aws.ToString(subnet.SubnetId)
+		matches, err := f.matches(id, input.SubnetIds, input.Filters, map[string]string{
+			subnetIDFilterName: id,
+			vpcIDFilterName:    aws.ToString(subnet.VpcId),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			subnet.Tags = f.visibleTags(id)
+			subnets = append(subnets, subnet)
+		}
+	}
+	if err := checkFound("InvalidSubnetID.NotFound", "subnet", input.SubnetIds, len(subnets)); err != nil {
+		return nil, err
+	}
+	page, next, err := paginate(subnets, input.MaxResults, input.NextToken, f.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeSubnetsOutput{Subnets: page, NextToken: next}, nil
+}
+
+func (f *EC2) DescribeRouteTables(_ context.Context, input *ec2.DescribeRouteTablesInput, _ ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("DescribeRouteTables", nil); err != nil {
+		return nil, err
+	}
+	defer f.propagateTags()
+
+	var routeTables []ec2types.RouteTable
+	for _, rt := range f.routeTables {
+		id := aws.ToString(rt.RouteTableId)
+		matches, err := f.matches(id, input.RouteTableIds, input.Filters, map[string]string{vpcIDFilterName: aws.ToString(rt.VpcId)})
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			rt.Tags = f.visibleTags(id)
+			routeTables = append(routeTables, rt)
+		}
+	}
+	if err := checkFound("InvalidRouteTableID.NotFound", "route table", input.RouteTableIds, len(routeTables)); err != nil {
+		return nil, err
+	}
+	page, next, err := paginate(routeTables, input.MaxResults, input.NextToken, f.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeRouteTablesOutput{RouteTables: page, NextToken: next}, nil
+}
+
+// DescribeInstances returns each matching instance in its own reservation.
+func (f *EC2) DescribeInstances(_ context.Context, input *ec2.DescribeInstancesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("DescribeInstances", nil); err != nil {
+		return nil, err
+	}
+	defer f.propagateTags()
+
+	var reservations []ec2types.Reservation
+	for _, instance := range f.instances {
+		id := aws.ToString(instance.InstanceId)
+		attributes := map[string]string{
+			vpcIDFilterName:    aws.ToString(instance.VpcId),
+			subnetIDFilterName: aws.ToString(instance.SubnetId),
+		}
+		if instance.State != nil {
+			attributes[instanceStateFilterName] = string(instance.State.Name)
+		}
+		matches, err := f.matches(id, input.InstanceIds, input.Filters, attributes)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			instance.Tags = f.visibleTags(id)
+			reservations = append(reservations, ec2types.Reservation{Instances: []ec2types.Instance{instance}})
+		}
+	}
+	if err := checkFound("InvalidInstanceID.NotFound", "instance", input.InstanceIds, len(reservations)); err != nil {
+		return nil, err
+	}
+	page, next, err := paginate(reservations, input.MaxResults, input.NextToken, f.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &ec2.DescribeInstancesOutput{Reservations: page, NextToken: next}, nil
+}
+
+func (f *EC2) CreateTags(_ context.Context, input *ec2.CreateTagsInput, _ ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	failed := f.call("CreateTags", input.Resources)
+	if err := f.checkResources(input.Resources); err != nil {
+		return nil, err
+	}
+	for _, id := range input.Resources {
+		if failed != nil && f.isFailedResource(id) {
+			continue
+		}
+		for _, tag := range input.Tags {
+			f.changeTag(id, aws.ToString(tag.Key), aws.String(aws.ToString(tag.Value)))
+		}
+	}
+	if failed != nil {
+		return nil, failed
+	}
+	return &ec2.CreateTagsOutput{}, nil
+}
+
+// DeleteTags removes the tags of the given resources. A tag with a value is only removed if the value matches.
+func (f *EC2) DeleteTags(_ context.Context, input *ec2.DeleteTagsInput, _ ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	failed := f.call("DeleteTags", input.Resources)
+	if err := f.checkResources(input.Resources); err != nil {
+		return nil, err
+	}
+	for _, id := range input.Resources {
+		if failed != nil && f.isFailedResource(id) {
+			continue
+		}
+		for _, tag := range input.Tags {
+			key := aws.ToString(tag.Key)
+			if tag.Value != nil && f.currentTags(id)[key] != aws.ToString(tag.Value) {
+				continue
+			}
+			f.changeTag(id, key, nil)
+		}
+	}
+	if failed != nil {
+		return nil, failed
+	}
+	return &ec2.DeleteTagsOutput{}, nil
+}
+
+// call counts the request to the given operation and returns the error of the first matching fault.
+// The faults limited to some resources only match the requests for these resources.
+func (f *EC2) call(operation string, resourceIDs []string) error {
+	f.calls[operation]++
+	for i, fault := range f.faults {
+		if fault.Operation != "" && fault.Operation != operation {
+			continue
+		}
+		if len(fault.ResourceIDs) > 0 && !containsAny(fault.ResourceIDs, resourceIDs) {
+			continue
+		}
+		if fault.Count > 0 {
+			fault.Count--
+			if fault.Count == 0 {
+				f.faults = append(f.faults[:i], f.faults[i+1:]...)
+			}
+		}
+		f.failedResources = fault.ResourceIDs
+		return fault.Err
+	}
+	f.failedResources = nil
+	return nil
+}
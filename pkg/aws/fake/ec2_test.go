@@ -0,0 +1,268 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+
+	albaws "github.com/openshift/aws-load-balancer-operator/pkg/aws"
+)
+
+func tags(kv ...string) []ec2types.Tag {
+	var tags []ec2types.Tag
+	for i := 0; i < len(kv); i += 2 {
+		tags = append(tags, ec2types.Tag{Key: aws.String(kv[i]), Value: aws.String(kv[i+1])})
+	}
+	return tags
+}
+
+func testEC2() *EC2 {
+	f := NewEC2()
+	f.AddVPC(ec2types.Vpc{VpcId: aws.String("vpc-1")})
+	f.AddVPC(ec2types.Vpc{VpcId: aws.String("vpc-2"), Tags: tags("kubernetes.io/cluster/test", "owned")})
+	for i := 1; i <= 5; i++ {
+		f.AddSubnet(ec2types.Subnet{
+			SubnetId: aws.String(fmt.Sprintf("subnet-%d", i)),
+			VpcId:    aws.String("vpc-1"),
+			Tags:     tags("kubernetes.io/cluster/test", "shared", "Name", fmt.Sprintf("test-subnet-%d", i)),
+		})
+	}
+	f.AddSubnet(ec2types.Subnet{SubnetId: aws.String("subnet-other"), VpcId: aws.String("vpc-2")})
+	f.AddRouteTable(ec2types.RouteTable{RouteTableId: aws.String("rtb-1"), VpcId: aws.String("vpc-1")})
+	f.AddInstance(ec2types.Instance{
+		InstanceId: aws.String("i-1"),
+		VpcId:      aws.String("vpc-1"),
+		State:      &ec2types.InstanceState{Name: ec2types.InstanceStateNameRunning},
+		Tags:       tags("kubernetes.io/cluster/test", "owned", "Name", "test-master-0"),
+	})
+	f.AddInstance(ec2types.Instance{
+		InstanceId: aws.String("i-2"),
+		State:      &ec2types.InstanceState{Name: ec2types.InstanceStateNameTerminated},
+		Tags:       tags("kubernetes.io/cluster/test", "owned", "Name", "test-master-1"),
+	})
+	return f
+}
+
+// listSubnets lists all the pages of subnets matching the input and returns their IDs.
+func listSubnets(t *testing.T, client albaws.EC2Client, input *ec2.DescribeSubnetsInput) ([]string, error) {
+	t.Helper()
+	var ids []string
+	paginator := ec2.NewDescribeSubnetsPaginator(client, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range output.Subnets {
+			ids = append(ids, aws.ToString(s.SubnetId))
+		}
+	}
+	return ids, nil
+}
+
+func filter(name string, values ...string) ec2types.Filter {
+	return ec2types.Filter{Name: aws.String(name), Values: values}
+}
+
+func TestDescribe(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		input        *ec2.DescribeSubnetsInput
+		pageSize     int
+		expectedIDs  []string
+		expectedCode string
+	}{
+		{
+			name:        "by IDs",
+			input:       &ec2.DescribeSubnetsInput{SubnetIds: []string{"subnet-2", "subnet-other"}},
+			expectedIDs: []string{"subnet-2", "subnet-other"},
+		},
+		{
+			name:         "unknown ID",
+			input:        &ec2.DescribeSubnetsInput{SubnetIds: []string{"subnet-2", "subnet-unknown"}},
+			expectedCode: "InvalidSubnetID.NotFound",
+		},
+		{
+			name:        "by tag key",
+			input:       &ec2.DescribeSubnetsInput{Filters: []ec2types.Filter{filter("tag-key", "kubernetes.io/cluster/test")}},
+			expectedIDs: []string{"subnet-1", "subnet-2", "subnet-3", "subnet-4", "subnet-5"},
+		},
+		{
+			name: "by VPC and tag value with wildcards",
+			input: &ec2.DescribeSubnetsInput{Filters: []ec2types.Filter{
+				filter("vpc-id", "vpc-1"),
+				filter("tag:Name", "test-subnet-?", "other-*"),
+				filter("tag:kubernetes.io/cluster/test", "*"),
+			}},
+			expectedIDs: []string{"subnet-1", "subnet-2", "subnet-3", "subnet-4", "subnet-5"},
+		},
+		{
+			name:        "no match",
+			input:       &ec2.DescribeSubnetsInput{Filters: []ec2types.Filter{filter("tag:Name", "unknown")}},
+			expectedIDs: nil,
+		},
+		{
+			name:        "paginated",
+			input:       &ec2.DescribeSubnetsInput{},
+			pageSize:    2,
+			expectedIDs: []string{"subnet-1", "subnet-2", "subnet-3", "subnet-4", "subnet-5", "subnet-other"},
+		},
+		{
+			name:         "unsupported filter",
+			input:        &ec2.DescribeSubnetsInput{Filters: []ec2types.Filter{filter("availability-zone", "us-east-1a")}},
+			expectedCode: "InvalidParameterValue",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := testEC2()
+			f.PageSize = tc.pageSize
+			ids, err := listSubnets(t, f, tc.input)
+			if tc.expectedCode != "" {
+				var apiErr smithy.APIError
+				if !errors.As(err, &apiErr) || apiErr.ErrorCode() != tc.expectedCode {
+					t.Fatalf("expected error with code %s, got %v", tc.expectedCode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(ids, tc.expectedIDs) {
+				t.Errorf("expected subnets %v, got %v", tc.expectedIDs, ids)
+			}
+			expectedCalls := 1
+			if tc.pageSize > 0 {
+				expectedCalls = (len(tc.expectedIDs) + tc.pageSize - 1) / tc.pageSize
+			}
+			if calls := f.Calls("DescribeSubnets"); calls != expectedCalls {
+				t.Errorf("expected %d DescribeSubnets calls, got %d", expectedCalls, calls)
+			}
+		})
+	}
+}
+
+func TestGetVPCId(t *testing.T) {
+	f := NewEC2()
+	f.AddSubnet(ec2types.Subnet{SubnetId: aws.String("subnet-1"), VpcId: aws.String("vpc-1")})
+	f.AddInstance(ec2types.Instance{
+		InstanceId: aws.String("i-1"),
+		VpcId:      aws.String("vpc-1"),
+		State:      &ec2types.InstanceState{Name: ec2types.InstanceStateNameRunning},
+		Tags:       tags("kubernetes.io/cluster/test", "owned", "Name", "test-master-0"),
+	})
+	f.AddInstance(ec2types.Instance{
+		InstanceId: aws.String("i-2"),
+		State:      &ec2types.InstanceState{Name: ec2types.InstanceStateNameTerminated},
+		Tags:       tags("kubernetes.io/cluster/test", "owned", "Name", "test-master-1"),
+	})
+
+	vpcID, source, err := albaws.GetVPCId(context.Background(), f, "test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if vpcID != "vpc-1" || source != albaws.VPCSourceControlPlaneInstances {
+		t.Errorf("expected VPC vpc-1 from %s, got %s from %s", albaws.VPCSourceControlPlaneInstances, vpcID, source)
+	}
+}
+
+func TestTags(t *testing.T) {
+	f := testEC2()
+	ctx := context.Background()
+	if _, err := f.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []string{"subnet-1", "subnet-2"},
+		Tags:      tags("kubernetes.io/role/elb", "1"),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ids, err := listSubnets(t, f, &ec2.DescribeSubnetsInput{Filters: []ec2types.Filter{filter("tag-key", "kubernetes.io/role/elb")}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"subnet-1", "subnet-2"}) {
+		t.Errorf("expected tagged subnets [subnet-1 subnet-2], got %v", ids)
+	}
+
+	// a tag with a value is only removed if the value matches
+	if _, err := f.DeleteTags(ctx, &ec2.DeleteTagsInput{
+		Resources: []string{"subnet-1", "subnet-2"},
+		Tags:      []ec2types.Tag{{Key: aws.String("Name"), Value: aws.String("test-subnet-1")}, {Key: aws.String("kubernetes.io/role/elb")}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := map[string]string{"kubernetes.io/cluster/test": "shared"}; !reflect.DeepEqual(f.Tags("subnet-1"), expected) {
+		t.Errorf("expected tags %v, got %v", expected, f.Tags("subnet-1"))
+	}
+	if expected := map[string]string{"kubernetes.io/cluster/test": "shared", "Name": "test-subnet-2"}; !reflect.DeepEqual(f.Tags("subnet-2"), expected) {
+		t.Errorf("expected tags %v, got %v", expected, f.Tags("subnet-2"))
+	}
+
+	_, err = f.CreateTags(ctx, &ec2.CreateTagsInput{Resources: []string{"subnet-unknown"}, Tags: tags("a", "b")})
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "InvalidID" {
+		t.Errorf("expected InvalidID error, got %v", err)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	f := testEC2()
+	ctx := context.Background()
+	f.InjectFault(Fault{Operation: "DescribeSubnets", Err: ThrottlingError(), Count: 2})
+	f.InjectFault(Fault{Operation: "CreateTags", Err: ThrottlingError(), ResourceIDs: []string{"subnet-2"}, Count: 1})
+
+	// the instrumented client classifies the errors of the fake like the ones of the EC2 API
+	client := albaws.NewInstrumentedClient(f)
+	for i := 0; i < 2; i++ {
+		if _, err := client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{}); !albaws.IsThrottled(err) {
+			t.Errorf("expected request %d to be throttled, got %v", i+1, err)
+		}
+	}
+	if _, err := client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{}); err != nil {
+		t.Errorf("expected the fault to be exhausted, got %v", err)
+	}
+
+	// the fault doesn't match the requests for other resources
+	if _, err := f.CreateTags(ctx, &ec2.CreateTagsInput{Resources: []string{"subnet-1"}, Tags: tags("a", "1")}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// partial failure: subnet-3 is tagged, subnet-2 is not
+	if _, err := f.CreateTags(ctx, &ec2.CreateTagsInput{Resources: []string{"subnet-2", "subnet-3"}, Tags: tags("b", "1")}); err == nil {
+		t.Errorf("expected the CreateTags request to fail")
+	}
+	if _, found := f.Tags("subnet-2")["b"]; found {
+		t.Errorf("expected subnet-2 not to be tagged")
+	}
+	if _, found := f.Tags("subnet-3")["b"]; !found {
+		t.Errorf("expected subnet-3 to be tagged")
+	}
+	if calls := f.Calls("CreateTags"); calls != 2 {
+		t.Errorf("expected 2 CreateTags calls, got %d", calls)
+	}
+}
+
+func TestTagPropagationDelay(t *testing.T) {
+	f := testEC2().SetTagPropagationDelay(2)
+	ctx := context.Background()
+	if _, err := f.CreateTags(ctx, &ec2.CreateTagsInput{Resources: []string{"subnet-1"}, Tags: tags("kubernetes.io/role/elb", "1")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, found := f.Tags("subnet-1")["kubernetes.io/role/elb"]; !found {
+		t.Errorf("expected the pending tag to be returned by Tags")
+	}
+	input := &ec2.DescribeSubnetsInput{Filters: []ec2types.Filter{filter("tag-key", "kubernetes.io/role/elb")}}
+	for i, expected := range [][]string{nil, nil, {"subnet-1"}} {
+		ids, err := listSubnets(t, f, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(ids, expected) {
+			t.Errorf("expected request %d to return %v, got %v", i+1, expected, ids)
+		}
+	}
+}
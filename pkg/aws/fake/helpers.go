@@ -0,0 +1,220 @@
+package fake
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+)
+
+// matches checks whether the resource with the given ID, filter attributes and visible tags
+// is selected by the IDs and the filters of a Describe request.
+func (f *EC2) matches(id string, ids []string, filters []ec2types.Filter, attributes map[string]string) (bool, error) {
+	if len(ids) > 0 && !containsAny(ids, []string{id}) {
+		return false, nil
+	}
+	tags := f.tags[id]
+	for _, filter := range filters {
+		name := aws.ToString(filter.Name)
+		var candidates []string
+		switch {
+		case name == tagKeyFilterName:
+			for key := range tags {
+				candidates = append(candidates, key)
+			}
+		case strings.HasPrefix(name, tagFilterNamePrefix):
+			if value, found := tags[strings.TrimPrefix(name, tagFilterNamePrefix)]; found {
+				candidates = []string{value}
+			}
+		default:
+			value, supported := attributes[name]
+			if !supported {
+				return false, &smithy.GenericAPIError{
+					Code:    "InvalidParameterValue",
+					Message: fmt.Sprintf("The filter '%s' is invalid", name),
+					Fault:   smithy.FaultClient,
+				}
+			}
+			candidates = []string{value}
+		}
+		if !matchesAnyPattern(filter.Values, candidates) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchesAnyPattern checks whether one of the values matches one of the filter patterns.
+// The patterns support the * and ? wildcards like the filters of the EC2 API.
+func matchesAnyPattern(patterns, values []string) bool {
+	for _, p := range patterns {
+		expr := strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(p))
+		re := regexp.MustCompile("^" + expr + "$")
+		for _, v := range values {
+			if re.MatchString(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// paginate returns the page of the items starting at the given token and the token of the next page.
+// The size of the page is set by maxResults or by the default page size.
+func paginate[T any](items []T, maxResults *int32, token *string, pageSize int) ([]T, *string, error) {
+	start := 0
+	if token != nil {
+		var err error
+		start, err = strconv.Atoi(aws.ToString(token))
+		if err != nil || start < 0 || start > len(items) {
+			return nil, nil, &smithy.GenericAPIError{
+				Code:    "InvalidPaginationToken",
+				Message: fmt.Sprintf("The token '%s' is invalid", aws.ToString(token)),
+				Fault:   smithy.FaultClient,
+			}
+		}
+	}
+	if maxResults != nil {
+		pageSize = int(aws.ToInt32(maxResults))
+	}
+	if pageSize <= 0 || start+pageSize >= len(items) {
+		return items[start:], nil, nil
+	}
+	return items[start : start+pageSize], aws.String(strconv.Itoa(start + pageSize)), nil
+}
+
+// checkFound returns the not found error of the EC2 API if some of the requested IDs don't exist.
+func checkFound(code, kind string, ids []string, found int) error {
+	if len(ids) == 0 || found == len(ids) {
+		return nil
+	}
+	return &smithy.GenericAPIError{
+		Code:    code,
+		Message: fmt.Sprintf("The %s IDs %v do not all exist", kind, ids),
+		Fault:   smithy.FaultClient,
+	}
+}
+
+// checkResources returns the error of the EC2 API if some of the resources to tag don't exist.
+func (f *EC2) checkResources(ids []string) error {
+	known := make(map[string]bool)
+	for _, vpc := range f.vpcs {
+		known[aws.ToString(vpc.VpcId)] = true
+	}
+	for _, subnet := range f.subnets {
+		known[aws.ToString(subnet.SubnetId)] = true
+	}
+	for _, rt := range f.routeTables {
+		known[aws.ToString(rt.RouteTableId)] = true
+	}
+	for _, instance := range f.instances {
+		known[aws.ToString(instance.InstanceId)] = true
+	}
+	for _, id := range ids {
+		if !known[id] {
+			return &smithy.GenericAPIError{
+				Code:    "InvalidID",
+				Message: fmt.Sprintf("The ID '%s' is not valid", id),
+				Fault:   smithy.FaultClient,
+			}
+		}
+	}
+	return nil
+}
+
+// isFailedResource checks whether the given resource is affected by the fault returned by the last request.
+// All the resources are affected if the fault isn't limited to some resources.
+func (f *EC2) isFailedResource(id string) bool {
+	return len(f.failedResources) == 0 || containsAny(f.failedResources, []string{id})
+}
+
+// setTags sets the initial tags of a resource, they are visible right away.
+func (f *EC2) setTags(id string, tags []ec2types.Tag) {
+	f.tags[id] = make(map[string]string, len(tags))
+	for _, tag := range tags {
+		f.tags[id][aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+}
+
+// changeTag creates or removes the tag of a resource, the change is delayed by the propagation delay.
+func (f *EC2) changeTag(id, key string, value *string) {
+	change := tagChange{resourceID: id, key: key, value: value, remaining: f.propagationDelay}
+	if change.remaining <= 0 {
+		f.applyTagChange(change)
+		return
+	}
+	f.pendingTags = append(f.pendingTags, change)
+}
+
+func (f *EC2) applyTagChange(change tagChange) {
+	if change.value == nil {
+		delete(f.tags[change.resourceID], change.key)
+		return
+	}
+	if f.tags[change.resourceID] == nil {
+		f.tags[change.resourceID] = make(map[string]string)
+	}
+	f.tags[change.resourceID][change.key] = *change.value
+}
+
+// propagateTags counts a Describe request for the pending tag changes
+// and applies the changes which were hidden from enough requests.
+func (f *EC2) propagateTags() {
+	var pending []tagChange
+	for _, c := range f.pendingTags {
+		c.remaining--
+		if c.remaining > 0 {
+			pending = append(pending, c)
+			continue
+		}
+		f.applyTagChange(c)
+	}
+	f.pendingTags = pending
+}
+
+// currentTags returns the visible tags of the resource with the pending changes applied.
+func (f *EC2) currentTags(id string) map[string]string {
+	tags := make(map[string]string, len(f.tags[id]))
+	for k, v := range f.tags[id] {
+		tags[k] = v
+	}
+	for _, c := range f.pendingTags {
+		if c.resourceID != id {
+			continue
+		}
+		if c.value == nil {
+			delete(tags, c.key)
+		} else {
+			tags[c.key] = *c.value
+		}
+	}
+	return tags
+}
+
+// visibleTags returns the visible tags of the resource sorted by key.
+func (f *EC2) visibleTags(id string) []ec2types.Tag {
+	var tags []ec2types.Tag
+	for k, v := range f.tags[id] {
+		tags = append(tags, ec2types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return aws.ToString(tags[i].Key) < aws.ToString(tags[j].Key)
+	})
+	return tags
+}
+
+func containsAny(set, values []string) bool {
+	for _, s := range set {
+		for _, v := range values {
+			if s == v {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// unknownErrorCode is the error code reported for the errors which don't come from the AWS API,
+	// like the network or the credentials errors.
+	unknownErrorCode = "Unknown"
+)
+
+var (
+	apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "aws_load_balancer_operator_aws_api_request_duration_seconds",
+		Help:    "Latency of the AWS API requests made by the operator.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"operation"})
+
+	apiRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aws_load_balancer_operator_aws_api_request_errors_total",
+		Help: "Number of the failed AWS API requests made by the operator.",
+	}, []string{"operation", "code"})
+
+	apiRequestRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aws_load_balancer_operator_aws_api_request_retries_total",
+		Help: "Number of the retried attempts of the AWS API requests made by the operator.",
+	}, []string{"operation"})
+
+	apiRequestThrottles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aws_load_balancer_operator_aws_api_request_throttles_total",
+		Help: "Number of the attempts of the AWS API requests made by the operator which were throttled.",
+	}, []string{"operation"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiRequestDuration, apiRequestErrors, apiRequestRetries, apiRequestThrottles)
+}
+
+// instrumentedClient records the latency and the errors of the requests made with the wrapped client.
+// The errors of the wrapped client are returned as RequestError.
+type instrumentedClient struct {
+	client EC2Client
+}
+
+// NewInstrumentedClient returns an EC2Client which exposes the metrics of the requests made with the given client.
+func NewInstrumentedClient(client EC2Client) EC2Client {
+	return &instrumentedClient{client: client}
+}
+
+func (c *instrumentedClient) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	start := time.Now()
+	output, err := c.client.DescribeVpcs(ctx, params, optFns...)
+	observeRequest("DescribeVpcs", start, err)
+	return output, newRequestError("DescribeVpcs", err)
+}
+
+func (c *instrumentedClient) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	start := time.Now()
+	output, err := c.client.DescribeInstances(ctx, params, optFns...)
+	observeRequest("DescribeInstances", start, err)
+	return output, newRequestError("DescribeInstances", err)
+}
+
+func (c *instrumentedClient) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	start := time.Now()
+	output, err := c.client.DescribeSubnets(ctx, params, optFns...)
+	observeRequest("DescribeSubnets", start, err)
+	return output, newRequestError("DescribeSubnets", err)
+}
+
+func (c *instrumentedClient) DescribeRouteTables(ctx context.Context, params *ec2.DescribeRouteTablesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error) {
+	start := time.Now()
+	output, err := c.client.DescribeRouteTables(ctx, params, optFns...)
+	observeRequest("DescribeRouteTables", start, err)
+	return output, newRequestError("DescribeRouteTables", err)
+}
+
+func (c *instrumentedClient) CreateTags(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+	start := time.Now()
+	output, err := c.client.CreateTags(ctx, params, optFns...)
+	observeRequest("CreateTags", start, err)
+	return output, newRequestError("CreateTags", err)
+}
+
+func (c *instrumentedClient) DeleteTags(ctx context.Context, params *ec2.DeleteTagsInput, optFns ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error) {
+	start := time.Now()
+	output, err := c.client.DeleteTags(ctx, params, optFns...)
+	observeRequest("DeleteTags", start, err)
+	return output, newRequestError("DeleteTags", err)
+}
+
+// observeRequest records the latency of the request to the given operation
+// and counts the request as failed if an error is returned.
+func observeRequest(operation string, start time.Time, err error) {
+	apiRequestDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		apiRequestErrors.WithLabelValues(operation, errorCode(err)).Inc()
+	}
+}
+
+// errorCode returns the code of the AWS API error.
+func errorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return unknownErrorCode
+}
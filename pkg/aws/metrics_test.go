@@ -0,0 +1,79 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+type failingEC2Client struct {
+	EC2Client
+	err error
+}
+
+func (c *failingEC2Client) CreateTags(context.Context, *ec2.CreateTagsInput, ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+	return nil, c.err
+}
+
+func (c *failingEC2Client) DeleteTags(context.Context, *ec2.DeleteTagsInput, ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error) {
+	return &ec2.DeleteTagsOutput{}, nil
+}
+
+func TestInstrumentedClient(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		err          error
+		expectedCode string
+	}{
+		{
+			name:         "AWS API error",
+			err:          &smithy.GenericAPIError{Code: "UnauthorizedOperation", Message: "not authorized"},
+			expectedCode: "UnauthorizedOperation",
+		},
+		{
+			name:         "other error",
+			err:          errors.New("connection refused"),
+			expectedCode: unknownErrorCode,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			apiRequestDuration.Reset()
+			apiRequestErrors.Reset()
+			client := NewInstrumentedClient(&failingEC2Client{err: tc.err})
+
+			if _, err := client.CreateTags(context.Background(), &ec2.CreateTagsInput{}); !errors.Is(err, tc.err) {
+				t.Errorf("expected error %v, got %v", tc.err, err)
+			}
+			if _, err := client.DeleteTags(context.Background(), &ec2.DeleteTagsInput{}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			for _, operation := range []string{"CreateTags", "DeleteTags"} {
+				var m dto.Metric
+				if err := apiRequestDuration.WithLabelValues(operation).(prometheus.Histogram).Write(&m); err != nil {
+					t.Fatalf("failed to read the latency of %s: %v", operation, err)
+				}
+				if count := m.GetHistogram().GetSampleCount(); count != 1 {
+					t.Errorf("expected the latency of 1 %s request, got %d", operation, count)
+				}
+			}
+			var m dto.Metric
+			if err := apiRequestErrors.WithLabelValues("CreateTags", tc.expectedCode).Write(&m); err != nil {
+				t.Fatalf("failed to read the CreateTags errors: %v", err)
+			}
+			if value := m.GetCounter().GetValue(); value != 1 {
+				t.Errorf("expected 1 CreateTags error with code %s, got %v", tc.expectedCode, value)
+			}
+			ch := make(chan prometheus.Metric, 10)
+			apiRequestErrors.Collect(ch)
+			if count := len(ch); count != 1 {
+				t.Errorf("expected only the CreateTags errors to be counted, got %d series", count)
+			}
+		})
+	}
+}
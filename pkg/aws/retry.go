@@ -0,0 +1,76 @@
+package aws
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+)
+
+const (
+	// retryMiddlewareID is the ID of the SDK middleware which retries the failed attempts of a request.
+	retryMiddlewareID = "Retry"
+	// attemptMetricsMiddlewareID is the ID of the middleware which records the metrics of each attempt.
+	attemptMetricsMiddlewareID = "AttemptMetrics"
+)
+
+// RetryOptions configures the retries of the failed AWS API requests.
+type RetryOptions struct {
+	// MaxAttempts is the maximum number of attempts made for a request, including the first one.
+	MaxAttempts int
+	// MaxBackoff is the maximum delay between two attempts of a request.
+	// The delay grows exponentially with a random jitter up to this value.
+	MaxBackoff time.Duration
+	// AdaptiveRateLimiting enables the client side rate limiting of the requests
+	// when they get throttled by the AWS API.
+	AdaptiveRateLimiting bool
+}
+
+// DefaultRetryOptions are the retry options used by the operator if not set by the flags.
+var DefaultRetryOptions = RetryOptions{
+	MaxAttempts:          5,
+	MaxBackoff:           20 * time.Second,
+	AdaptiveRateLimiting: true,
+}
+
+// NewRetryer returns the retryer for the given options.
+// The same retryer has to be shared by the clients to rate limit all the requests made by the operator.
+func NewRetryer(options RetryOptions) aws.Retryer {
+	standardOptions := func(o *retry.StandardOptions) {
+		if options.MaxAttempts > 0 {
+			o.MaxAttempts = options.MaxAttempts
+		}
+		if options.MaxBackoff > 0 {
+			o.MaxBackoff = options.MaxBackoff
+			o.Backoff = retry.NewExponentialJitterBackoff(options.MaxBackoff)
+		}
+	}
+	if options.AdaptiveRateLimiting {
+		return retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+			o.StandardOptions = append(o.StandardOptions, standardOptions)
+		})
+	}
+	return retry.NewStandard(standardOptions)
+}
+
+// addAttemptMetricsMiddleware adds the middleware which counts the retried and the throttled attempts of the request.
+// The middleware is added after the retry middleware so that it's called for each attempt.
+func addAttemptMetricsMiddleware(stack *middleware.Stack) error {
+	attempts := 0
+	return stack.Finalize.Insert(middleware.FinalizeMiddlewareFunc(attemptMetricsMiddlewareID,
+		func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			attempts++
+			operation := awsmiddleware.GetOperationName(ctx)
+			if attempts > 1 {
+				apiRequestRetries.WithLabelValues(operation).Inc()
+			}
+			out, metadata, err := next.HandleFinalize(ctx, in)
+			if err != nil && isThrottleError(err) {
+				apiRequestThrottles.WithLabelValues(operation).Inc()
+			}
+			return out, metadata, err
+		}), retryMiddlewareID, middleware.After)
+}
@@ -0,0 +1,156 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go/middleware"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const (
+	throttledResponse = `<Response><Errors><Error><Code>RequestLimitExceeded</Code><Message>Request limit exceeded.</Message></Error></Errors><RequestID>test</RequestID></Response>`
+	vpcsResponse      = `<DescribeVpcsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><requestId>test</requestId><vpcSet><item><vpcId>vpc-test</vpcId></item></vpcSet></DescribeVpcsResponse>`
+)
+
+func TestNewRetryer(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		options             RetryOptions
+		expectedMaxAttempts int
+		expectedAdaptive    bool
+	}{
+		{
+			name:                "default options",
+			options:             DefaultRetryOptions,
+			expectedMaxAttempts: 5,
+			expectedAdaptive:    true,
+		},
+		{
+			name:                "standard mode",
+			options:             RetryOptions{MaxAttempts: 10, MaxBackoff: time.Second},
+			expectedMaxAttempts: 10,
+		},
+		{
+			name:                "SDK defaults",
+			options:             RetryOptions{},
+			expectedMaxAttempts: retry.DefaultMaxAttempts,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			retryer := NewRetryer(tc.options)
+			if _, adaptive := retryer.(*retry.AdaptiveMode); adaptive != tc.expectedAdaptive {
+				t.Errorf("expected adaptive mode to be %t, got %T", tc.expectedAdaptive, retryer)
+			}
+			if retryer.MaxAttempts() != tc.expectedMaxAttempts {
+				t.Errorf("expected %d max attempts, got %d", tc.expectedMaxAttempts, retryer.MaxAttempts())
+			}
+			if tc.options.MaxBackoff > 0 {
+				for attempt := 1; attempt < 20; attempt++ {
+					delay, err := retryer.RetryDelay(attempt, &retry.MaxAttemptsError{})
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					if delay > tc.options.MaxBackoff {
+						t.Errorf("expected delay of attempt %d to be at most %v, got %v", attempt, tc.options.MaxBackoff, delay)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRetries(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		throttled         int32
+		expectedErr       string
+		expectedRequests  int32
+		expectedRetries   float64
+		expectedThrottles float64
+	}{
+		{
+			name:             "no throttling",
+			expectedRequests: 1,
+		},
+		{
+			name:              "throttled then succeeded",
+			throttled:         2,
+			expectedRequests:  3,
+			expectedRetries:   2,
+			expectedThrottles: 2,
+		},
+		{
+			name:              "throttled until max attempts",
+			throttled:         5,
+			expectedErr:       "exceeded maximum number of attempts, 3",
+			expectedRequests:  3,
+			expectedRetries:   2,
+			expectedThrottles: 3,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			apiRequestRetries.Reset()
+			apiRequestThrottles.Reset()
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if atomic.AddInt32(&requests, 1) <= tc.throttled {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					fmt.Fprint(w, throttledResponse)
+					return
+				}
+				fmt.Fprint(w, vpcsResponse)
+			}))
+			defer server.Close()
+
+			client := NewInstrumentedClient(ec2.New(ec2.Options{
+				Region:           "us-east-1",
+				Credentials:      aws.AnonymousCredentials{},
+				EndpointResolver: ec2.EndpointResolverFromURL(server.URL),
+				Retryer:          NewRetryer(RetryOptions{MaxAttempts: 3, MaxBackoff: time.Millisecond}),
+				APIOptions:       []func(*middleware.Stack) error{addAttemptMetricsMiddleware},
+			}))
+
+			output, err := client.DescribeVpcs(context.Background(), &ec2.DescribeVpcsInput{})
+			if tc.expectedErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q, got nil", tc.expectedErr)
+				}
+				if !IsThrottled(err) {
+					t.Errorf("expected a throttling error, got %v", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if len(output.Vpcs) != 1 || aws.ToString(output.Vpcs[0].VpcId) != "vpc-test" {
+					t.Errorf("unexpected VPCs %v", output.Vpcs)
+				}
+			}
+			if requests != tc.expectedRequests {
+				t.Errorf("expected %d requests, got %d", tc.expectedRequests, requests)
+			}
+			var m dto.Metric
+			if err := apiRequestRetries.WithLabelValues("DescribeVpcs").Write(&m); err != nil {
+				t.Fatalf("failed to read the retries: %v", err)
+			}
+			if value := m.GetCounter().GetValue(); value != tc.expectedRetries {
+				t.Errorf("expected %v retries, got %v", tc.expectedRetries, value)
+			}
+			if err := apiRequestThrottles.WithLabelValues("DescribeVpcs").Write(&m); err != nil {
+				t.Fatalf("failed to read the throttles: %v", err)
+			}
+			if value := m.GetCounter().GetValue(); value != tc.expectedThrottles {
+				t.Errorf("expected %v throttles, got %v", tc.expectedThrottles, value)
+			}
+		})
+	}
+}
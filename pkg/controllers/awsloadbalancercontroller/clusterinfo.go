@@ -0,0 +1,155 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	awstypes "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+	"github.com/openshift/aws-load-balancer-operator/pkg/aws"
+)
+
+// ensureClusterInfo resolves the cluster information used for the given controller and reports the result in its status.
+// The Infrastructure of the cluster is returned along with true if the AWS API requests failed temporarily
+// and the reconciliation has to be requeued.
+func (r *AWSLoadBalancerControllerReconciler) ensureClusterInfo(ctx context.Context, controller *albo.AWSLoadBalancerController, discoverVPC bool) (*configv1.Infrastructure, bool, error) {
+	infraConfig := &configv1.Infrastructure{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: clusterInfrastructureName}, infraConfig); err != nil {
+		return nil, false, fmt.Errorf("failed to get infrastructure %q: %w", clusterInfrastructureName, err)
+	}
+	clusterInfoErr := r.resolveClusterInfo(ctx, infraConfig, discoverVPC, time.Now())
+	if err := r.updateStatusConditions(ctx, controller, clusterInfoConditions(clusterInfoErr, r.controllerVPCSource(controller), controller.Generation)...); err != nil {
+		return nil, false, fmt.Errorf("failed to update status: %w", err)
+	}
+	if aws.IsTransient(clusterInfoErr) {
+		log.FromContext(ctx).Info("(Retrying) AWS API requests failed temporarily while resolving the cluster information", "error", clusterInfoErr)
+		return infraConfig, true, nil
+	}
+	if clusterInfoErr != nil {
+		r.eventf(controller, corev1.EventTypeWarning, clusterInfoResolutionFailedEventReason, "Failed to resolve the cluster information: %v", clusterInfoErr)
+		return nil, false, clusterInfoErr
+	}
+	return infraConfig, false, nil
+}
+
+// clusterInfoFromInfrastructure returns the cluster name and the AWS region from the status of the given Infrastructure.
+func clusterInfoFromInfrastructure(infra *configv1.Infrastructure) (clusterName, region string, err error) {
+	if infra.Status.InfrastructureName == "" {
+		return "", "", fmt.Errorf("could not get cluster name from Infrastructure %q status", infra.Name)
+	}
+	if infra.Status.PlatformStatus == nil || infra.Status.PlatformStatus.AWS == nil || infra.Status.PlatformStatus.AWS.Region == "" {
+		return "", "", fmt.Errorf("could not get AWS region from Infrastructure %q status", infra.Name)
+	}
+	return infra.Status.InfrastructureName, infra.Status.PlatformStatus.AWS.Region, nil
+}
+
+// resolveClusterInfo resolves the cluster name, the AWS region, the VPC ID and the EC2 client used for the controller.
+// The cluster name and the region are taken from the given Infrastructure, the VPC ID is looked up from the cluster tag
+// on the VPC, the subnets or the control plane instances of the cluster. The source of the VPC is kept on the reconciler.
+// The VPC is not looked up if discoverVPC is false, which is the case when the VPC is set in the spec of the controller.
+// The resolved information is cached on the reconciler and only resolved again when the cluster name or the region change,
+// or when the refresh interval elapsed since the last resolution. A new EC2 client is only created when the region changes.
+// The cluster information is considered fixed if the reconciler doesn't have an EC2 client factory.
+func (r *AWSLoadBalancerControllerReconciler) resolveClusterInfo(ctx context.Context, infra *configv1.Infrastructure, discoverVPC bool, now time.Time) error {
+	if r.NewEC2Client == nil {
+		return nil
+	}
+
+	clusterName, region, err := clusterInfoFromInfrastructure(infra)
+	if err != nil {
+		return err
+	}
+	if clusterName == r.ClusterName && region == r.AWSRegion && r.EC2Client != nil {
+		if !discoverVPC || r.VPCID != "" && !r.clusterInfoNeedsRefresh(now) {
+			return nil
+		}
+	}
+
+	ec2Client := r.EC2Client
+	if ec2Client == nil || region != r.AWSRegion {
+		ec2Client, err = r.NewEC2Client(ctx, region)
+		if err != nil {
+			return fmt.Errorf("failed to create EC2 client for region %q: %w", region, err)
+		}
+	}
+	// the reconciler runs a single worker, the resolved information
+	// is not accessed concurrently
+	if !discoverVPC {
+		if clusterName != r.ClusterName {
+			// the discovered VPC belongs to the previous cluster
+			r.VPCID = ""
+			r.vpcSource = ""
+		}
+		r.EC2Client = ec2Client
+		r.ClusterName = clusterName
+		r.AWSRegion = region
+		return nil
+	}
+
+	vpcID, vpcSource, err := aws.GetVPCId(ctx, ec2Client, clusterName)
+	if err != nil {
+		return fmt.Errorf("failed to get VPC ID of cluster %q: %w", clusterName, err)
+	}
+
+	if clusterName != r.ClusterName || region != r.AWSRegion || vpcID != r.VPCID || vpcSource != r.vpcSource {
+		log.FromContext(ctx).Info("resolved cluster information", "cluster", clusterName, "region", region, "vpc", vpcID, "vpcSource", vpcSource)
+	}
+	r.EC2Client = ec2Client
+	r.ClusterName = clusterName
+	r.AWSRegion = region
+	r.VPCID = vpcID
+	r.vpcSource = vpcSource
+	r.clusterInfoResolvedAt = now
+	return nil
+}
+
+// clusterInfoNeedsRefresh checks whether the refresh interval elapsed since the cluster information was resolved.
+// The periodic refresh is disabled if the interval is zero.
+func (r *AWSLoadBalancerControllerReconciler) clusterInfoNeedsRefresh(now time.Time) bool {
+	if r.ClusterInfoRefreshInterval <= 0 {
+		return false
+	}
+	return now.Sub(r.clusterInfoResolvedAt) >= r.ClusterInfoRefreshInterval
+}
+
+// controllerVPCID returns the VPC set in the spec of the controller or the discovered VPC of the cluster.
+func (r *AWSLoadBalancerControllerReconciler) controllerVPCID(controller *albo.AWSLoadBalancerController) string {
+	if controller.Spec.VPCID != "" {
+		return controller.Spec.VPCID
+	}
+	return r.VPCID
+}
+
+// controllerVPCSource returns the source from which the VPC of the controller was discovered.
+// An empty source is returned if the VPC is set in the spec of the controller or fixed on the reconciler.
+func (r *AWSLoadBalancerControllerReconciler) controllerVPCSource(controller *albo.AWSLoadBalancerController) aws.VPCSource {
+	if controller.Spec.VPCID != "" {
+		return ""
+	}
+	return r.vpcSource
+}
+
+// validateVPC verifies that the given VPC exists and that it contains the given subnets.
+func (r *AWSLoadBalancerControllerReconciler) validateVPC(ctx context.Context, vpcID string, subnets []ec2types.Subnet) error {
+	vpcs, err := r.EC2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{VpcIds: []string{vpcID}})
+	if err != nil {
+		return fmt.Errorf("failed to get VPC %s: %w", vpcID, err)
+	}
+	if len(vpcs.Vpcs) == 0 {
+		return fmt.Errorf("VPC %s not found", vpcID)
+	}
+	for _, s := range subnets {
+		if awstypes.ToString(s.VpcId) != vpcID {
+			return fmt.Errorf("subnet %s belongs to VPC %s instead of the VPC %s set in the spec", awstypes.ToString(s.SubnetId), awstypes.ToString(s.VpcId), vpcID)
+		}
+	}
+	return nil
+}
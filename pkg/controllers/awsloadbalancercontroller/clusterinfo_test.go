@@ -0,0 +1,453 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	awstypes "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/google/go-cmp/cmp"
+	configv1 "github.com/openshift/api/config/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+	"github.com/openshift/aws-load-balancer-operator/pkg/aws"
+	"github.com/openshift/aws-load-balancer-operator/pkg/utils/test"
+)
+
+func TestResolveClusterInfo(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	newClient := func() *testVPCClient {
+		return &testVPCClient{
+			vpcs: map[string]string{
+				"test-cluster":  "vpc-test",
+				"other-cluster": "vpc-other",
+			},
+			subnetVPCs:   map[string]string{"subnet-cluster": "vpc-subnet"},
+			instanceVPCs: map[string]string{"subnet-cluster": "vpc-instance", "instance-cluster": "vpc-instance"},
+		}
+	}
+
+	for _, tc := range []struct {
+		name            string
+		infraName       string
+		region          string
+		noFactory       bool
+		vpcOverridden   bool
+		cached          *AWSLoadBalancerControllerReconciler
+		refreshInterval time.Duration
+		factoryErr      error
+		expectedErr     string
+		expectedClients []string
+		expectedName    string
+		expectedRegion  string
+		expectedVPCID   string
+		expectedSource  aws.VPCSource
+	}{
+		{
+			name:            "first resolution",
+			infraName:       "test-cluster",
+			region:          "us-east-1",
+			expectedClients: []string{"us-east-1"},
+			expectedName:    "test-cluster",
+			expectedRegion:  "us-east-1",
+			expectedVPCID:   "vpc-test",
+			expectedSource:  aws.VPCSourceVPCTag,
+		},
+		{
+			name:      "fixed cluster information",
+			infraName: "other-cluster",
+			region:    "us-west-2",
+			noFactory: true,
+			cached: &AWSLoadBalancerControllerReconciler{
+				ClusterName: "test-cluster",
+				AWSRegion:   "us-east-1",
+				VPCID:       "vpc-test",
+			},
+			expectedName:   "test-cluster",
+			expectedRegion: "us-east-1",
+			expectedVPCID:  "vpc-test",
+		},
+		{
+			name:      "cached cluster information",
+			infraName: "test-cluster",
+			region:    "us-east-1",
+			cached: &AWSLoadBalancerControllerReconciler{
+				ClusterName:           "test-cluster",
+				AWSRegion:             "us-east-1",
+				VPCID:                 "vpc-cached",
+				clusterInfoResolvedAt: now.Add(-time.Minute),
+			},
+			refreshInterval: time.Hour,
+			expectedName:    "test-cluster",
+			expectedRegion:  "us-east-1",
+			expectedVPCID:   "vpc-cached",
+		},
+		{
+			name:      "refresh interval elapsed",
+			infraName: "test-cluster",
+			region:    "us-east-1",
+			cached: &AWSLoadBalancerControllerReconciler{
+				ClusterName:           "test-cluster",
+				AWSRegion:             "us-east-1",
+				VPCID:                 "vpc-cached",
+				clusterInfoResolvedAt: now.Add(-2 * time.Hour),
+			},
+			refreshInterval: time.Hour,
+			expectedName:    "test-cluster",
+			expectedRegion:  "us-east-1",
+			expectedVPCID:   "vpc-test",
+			expectedSource:  aws.VPCSourceVPCTag,
+		},
+		{
+			name:      "region changed",
+			infraName: "test-cluster",
+			region:    "eu-west-1",
+			cached: &AWSLoadBalancerControllerReconciler{
+				ClusterName:           "test-cluster",
+				AWSRegion:             "us-east-1",
+				VPCID:                 "vpc-cached",
+				clusterInfoResolvedAt: now,
+			},
+			expectedClients: []string{"eu-west-1"},
+			expectedName:    "test-cluster",
+			expectedRegion:  "eu-west-1",
+			expectedVPCID:   "vpc-test",
+			expectedSource:  aws.VPCSourceVPCTag,
+		},
+		{
+			name:      "cluster name changed",
+			infraName: "other-cluster",
+			region:    "us-east-1",
+			cached: &AWSLoadBalancerControllerReconciler{
+				ClusterName:           "test-cluster",
+				AWSRegion:             "us-east-1",
+				VPCID:                 "vpc-test",
+				clusterInfoResolvedAt: now,
+			},
+			expectedName:   "other-cluster",
+			expectedRegion: "us-east-1",
+			expectedVPCID:  "vpc-other",
+			expectedSource: aws.VPCSourceVPCTag,
+		},
+		{
+			name:            "VPC of the tagged subnets",
+			infraName:       "subnet-cluster",
+			region:          "us-east-1",
+			expectedClients: []string{"us-east-1"},
+			expectedName:    "subnet-cluster",
+			expectedRegion:  "us-east-1",
+			expectedVPCID:   "vpc-subnet",
+			expectedSource:  aws.VPCSourceSubnetTags,
+		},
+		{
+			name:            "VPC of the control plane instances",
+			infraName:       "instance-cluster",
+			region:          "us-east-1",
+			expectedClients: []string{"us-east-1"},
+			expectedName:    "instance-cluster",
+			expectedRegion:  "us-east-1",
+			expectedVPCID:   "vpc-instance",
+			expectedSource:  aws.VPCSourceControlPlaneInstances,
+		},
+		{
+			name:            "VPC set in the spec",
+			infraName:       "unknown-cluster",
+			region:          "us-east-1",
+			vpcOverridden:   true,
+			expectedClients: []string{"us-east-1"},
+			expectedName:    "unknown-cluster",
+			expectedRegion:  "us-east-1",
+		},
+		{
+			name:          "VPC set in the spec, cached cluster information",
+			infraName:     "test-cluster",
+			region:        "us-east-1",
+			vpcOverridden: true,
+			cached: &AWSLoadBalancerControllerReconciler{
+				ClusterName:           "test-cluster",
+				AWSRegion:             "us-east-1",
+				VPCID:                 "vpc-cached",
+				clusterInfoResolvedAt: now.Add(-2 * time.Hour),
+			},
+			refreshInterval: time.Hour,
+			expectedName:    "test-cluster",
+			expectedRegion:  "us-east-1",
+			expectedVPCID:   "vpc-cached",
+		},
+		{
+			name:        "region missing from infrastructure",
+			infraName:   "test-cluster",
+			expectedErr: `could not get AWS region from Infrastructure "cluster" status`,
+		},
+		{
+			name:        "cluster name missing from infrastructure",
+			region:      "us-east-1",
+			expectedErr: `could not get cluster name from Infrastructure "cluster" status`,
+		},
+		{
+			name:            "EC2 client cannot be created",
+			infraName:       "test-cluster",
+			region:          "us-east-1",
+			factoryErr:      fmt.Errorf("no credentials"),
+			expectedClients: []string{"us-east-1"},
+			expectedErr:     `failed to create EC2 client for region "us-east-1": no credentials`,
+		},
+		{
+			name:            "VPC not found",
+			infraName:       "unknown-cluster",
+			region:          "us-east-1",
+			expectedClients: []string{"us-east-1"},
+			expectedErr:     `failed to get VPC ID of cluster "unknown-cluster": no VPC, subnets or control plane instances with tag "kubernetes.io/cluster/unknown-cluster" found`,
+		},
+		{
+			name:      "VPC lookup failure keeps cached information",
+			infraName: "unknown-cluster",
+			region:    "us-east-1",
+			cached: &AWSLoadBalancerControllerReconciler{
+				ClusterName:           "test-cluster",
+				AWSRegion:             "us-east-1",
+				VPCID:                 "vpc-test",
+				clusterInfoResolvedAt: now,
+			},
+			expectedErr:    `failed to get VPC ID of cluster "unknown-cluster": no VPC, subnets or control plane instances with tag "kubernetes.io/cluster/unknown-cluster" found`,
+			expectedName:   "test-cluster",
+			expectedRegion: "us-east-1",
+			expectedVPCID:  "vpc-test",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &AWSLoadBalancerControllerReconciler{}
+			if tc.cached != nil {
+				r = tc.cached
+				r.EC2Client = newClient()
+			}
+			r.ClusterInfoRefreshInterval = tc.refreshInterval
+			var clients []string
+			if !tc.noFactory {
+				r.NewEC2Client = func(_ context.Context, region string) (aws.EC2Client, error) {
+					clients = append(clients, region)
+					if tc.factoryErr != nil {
+						return nil, tc.factoryErr
+					}
+					return newClient(), nil
+				}
+			}
+			infra := &configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterInfrastructureName},
+				Status:     configv1.InfrastructureStatus{InfrastructureName: tc.infraName},
+			}
+			if tc.region != "" {
+				infra.Status.PlatformStatus = &configv1.PlatformStatus{
+					Type: configv1.AWSPlatformType,
+					AWS:  &configv1.AWSPlatformStatus{Region: tc.region},
+				}
+			}
+
+			err := r.resolveClusterInfo(context.Background(), infra, !tc.vpcOverridden, now)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fmt.Sprint(clients) != fmt.Sprint(tc.expectedClients) {
+				t.Errorf("expected EC2 clients to be created for regions %v, got %v", tc.expectedClients, clients)
+			}
+			if r.ClusterName != tc.expectedName || r.AWSRegion != tc.expectedRegion || r.VPCID != tc.expectedVPCID {
+				t.Errorf("expected cluster %q, region %q and VPC %q, got cluster %q, region %q and VPC %q",
+					tc.expectedName, tc.expectedRegion, tc.expectedVPCID, r.ClusterName, r.AWSRegion, r.VPCID)
+			}
+			if r.vpcSource != tc.expectedSource {
+				t.Errorf("expected VPC source %q, got %q", tc.expectedSource, r.vpcSource)
+			}
+		})
+	}
+}
+
+func TestEnsureClusterInfo(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		factoryErr      error
+		expectedErr     string
+		expectedRequeue bool
+		expectedReason  string
+		expectedEvents  []string
+	}{
+		{
+			name:           "cluster information resolved",
+			expectedReason: "ClusterInfoResolved",
+		},
+		{
+			name:           "resolution failed",
+			factoryErr:     fmt.Errorf("no credentials"),
+			expectedErr:    `failed to create EC2 client for region "us-east-1": no credentials`,
+			expectedReason: "ClusterInfoResolutionFailed",
+			expectedEvents: []string{`Warning ClusterInfoResolutionFailed Failed to resolve the cluster information: failed to create EC2 client for region "us-east-1": no credentials`},
+		},
+		{
+			name:            "resolution failed temporarily",
+			factoryErr:      &aws.RequestError{Operation: "DescribeVpcs", Code: "RequestTimeout", Transient: true, Err: fmt.Errorf("request timed out")},
+			expectedRequeue: true,
+			expectedReason:  "AWSAPIUnavailable",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			controller := &albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+			// the status conditions are reported in the metrics of the controller
+			t.Cleanup(func() { forgetMetrics(controller.Name) })
+			infra := &configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterInfrastructureName},
+				Status: configv1.InfrastructureStatus{
+					InfrastructureName: "test-cluster",
+					PlatformStatus: &configv1.PlatformStatus{
+						Type: configv1.AWSPlatformType,
+						AWS:  &configv1.AWSPlatformStatus{Region: "us-east-1"},
+					},
+				},
+			}
+			testClient := fake.NewClientBuilder().
+				WithScheme(test.Scheme).
+				WithObjects(controller, infra).
+				WithStatusSubresource(&albo.AWSLoadBalancerController{}).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &AWSLoadBalancerControllerReconciler{
+				Client:   testClient,
+				Scheme:   test.Scheme,
+				Recorder: recorder,
+				NewEC2Client: func(_ context.Context, _ string) (aws.EC2Client, error) {
+					if tc.factoryErr != nil {
+						return nil, tc.factoryErr
+					}
+					return &testVPCClient{vpcs: map[string]string{"test-cluster": "vpc-test"}}, nil
+				},
+			}
+
+			_, requeue, err := r.ensureClusterInfo(ctx, controller, true)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if requeue != tc.expectedRequeue {
+				t.Errorf("expected requeue to be %t, got %t", tc.expectedRequeue, requeue)
+			}
+			if diff := cmp.Diff(tc.expectedEvents, recordedEvents(recorder)); diff != "" {
+				t.Errorf("unexpected events (-want +got):\n%s", diff)
+			}
+
+			var updated albo.AWSLoadBalancerController
+			if err := testClient.Get(ctx, types.NamespacedName{Name: controller.Name}, &updated); err != nil {
+				t.Fatalf("failed to get controller: %v", err)
+			}
+			cond := meta.FindStatusCondition(updated.Status.Conditions, ClusterInfoResolvedCondition)
+			if cond == nil || cond.Reason != tc.expectedReason {
+				t.Errorf("expected condition %s with reason %q, got %v", ClusterInfoResolvedCondition, tc.expectedReason, cond)
+			}
+		})
+	}
+}
+
+// testVPCClient returns the VPCs of the clusters matching the IDs
+// or the cluster tag key from the filter of the request.
+// The subnets and the instances are returned with the VPCs of the clusters matching the cluster tag key.
+type testVPCClient struct {
+	aws.SubnetClient
+	vpcs         map[string]string
+	subnetVPCs   map[string]string
+	instanceVPCs map[string]string
+}
+
+func (c *testVPCClient) DescribeSubnets(_ context.Context, input *ec2.DescribeSubnetsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	output := &ec2.DescribeSubnetsOutput{}
+	for name, vpcID := range c.subnetVPCs {
+		if input.Filters[0].Values[0] == fmt.Sprintf("kubernetes.io/cluster/%s", name) {
+			output.Subnets = append(output.Subnets, ec2types.Subnet{SubnetId: awstypes.String("subnet-" + name), VpcId: awstypes.String(vpcID)})
+		}
+	}
+	return output, nil
+}
+
+func (c *testVPCClient) DescribeInstances(_ context.Context, input *ec2.DescribeInstancesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	output := &ec2.DescribeInstancesOutput{}
+	for name, vpcID := range c.instanceVPCs {
+		if input.Filters[0].Values[0] == fmt.Sprintf("kubernetes.io/cluster/%s", name) {
+			output.Reservations = append(output.Reservations, ec2types.Reservation{
+				Instances: []ec2types.Instance{{InstanceId: awstypes.String("i-" + name), VpcId: awstypes.String(vpcID)}},
+			})
+		}
+	}
+	return output, nil
+}
+
+func (c *testVPCClient) DescribeVpcs(_ context.Context, input *ec2.DescribeVpcsInput, _ ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	output := &ec2.DescribeVpcsOutput{}
+	for name, vpcID := range c.vpcs {
+		if len(input.VpcIds) > 0 && input.VpcIds[0] == vpcID ||
+			len(input.Filters) > 0 && input.Filters[0].Values[0] == fmt.Sprintf("kubernetes.io/cluster/%s", name) {
+			output.Vpcs = append(output.Vpcs, ec2types.Vpc{VpcId: awstypes.String(vpcID)})
+		}
+	}
+	return output, nil
+}
+
+func TestValidateVPC(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		vpcID       string
+		subnets     []ec2types.Subnet
+		expectedErr string
+	}{
+		{
+			name:  "subnets in the VPC",
+			vpcID: "vpc-test",
+			subnets: []ec2types.Subnet{
+				{SubnetId: awstypes.String("subnet-1"), VpcId: awstypes.String("vpc-test")},
+				{SubnetId: awstypes.String("subnet-2"), VpcId: awstypes.String("vpc-test")},
+			},
+		},
+		{
+			name:        "VPC not found",
+			vpcID:       "vpc-unknown",
+			subnets:     []ec2types.Subnet{{SubnetId: awstypes.String("subnet-1"), VpcId: awstypes.String("vpc-unknown")}},
+			expectedErr: "VPC vpc-unknown not found",
+		},
+		{
+			name:  "subnet in another VPC",
+			vpcID: "vpc-test",
+			subnets: []ec2types.Subnet{
+				{SubnetId: awstypes.String("subnet-1"), VpcId: awstypes.String("vpc-test")},
+				{SubnetId: awstypes.String("subnet-2"), VpcId: awstypes.String("vpc-other")},
+			},
+			expectedErr: "subnet subnet-2 belongs to VPC vpc-other instead of the VPC vpc-test set in the spec",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &AWSLoadBalancerControllerReconciler{
+				EC2Client: &testVPCClient{vpcs: map[string]string{"test-cluster": "vpc-test", "other-cluster": "vpc-other"}},
+			}
+			err := r.validateVPC(context.Background(), tc.vpcID, tc.subnets)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -24,17 +24,22 @@ import (
 	arv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 
 	cco "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
 
 	configv1 "github.com/openshift/api/config/v1"
+	elbv1beta1 "sigs.k8s.io/aws-load-balancer-controller/apis/elbv2/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -45,7 +50,7 @@ import (
 )
 
 const (
-	// the name of the AWSLoadBalancerController resource which will be reconciled
+	// the name of the default AWSLoadBalancerController resource
 	controllerName = "cluster"
 	// clusterInfrastructureName is the name of the 'cluster' infrastructure object.
 	clusterInfrastructureName = "cluster"
@@ -57,6 +62,9 @@ const (
 	controllerResourcePrefix = "aws-load-balancer-controller"
 	// secretMissingReEnqueueDuration is the delay to re-enqueue.
 	secretMissingReEnqueueDuration = time.Second * 30
+	// classConflictReEnqueueDuration is the delay to re-enqueue an instance
+	// whose ingress class or load balancer class is already used by another instance.
+	classConflictReEnqueueDuration = time.Minute
 )
 
 // AWSLoadBalancerControllerReconciler reconciles a AWSLoadBalancerController object
@@ -70,6 +78,25 @@ type AWSLoadBalancerControllerReconciler struct {
 	VPCID                  string
 	AWSRegion              string
 	TrustedCAConfigMapName string
+	// NewEC2Client creates an EC2 client for the given AWS region.
+	// When it's set the cluster name, the AWS region, the VPC ID and the EC2 client
+	// are resolved during the reconciliation instead of being fixed by the fields above.
+	NewEC2Client func(ctx context.Context, region string) (aws.EC2Client, error)
+	// ClusterInfoRefreshInterval is the interval at which the resolved cluster information is refreshed.
+	// The periodic refresh is disabled if the interval is zero.
+	ClusterInfoRefreshInterval time.Duration
+	// SubnetResyncInterval is the interval at which the subnets are re-discovered and re-tagged.
+	// The periodic synchronization is disabled if the interval is zero.
+	SubnetResyncInterval time.Duration
+	// Recorder records the events on the AWSLoadBalancerController resources.
+	Recorder record.EventRecorder
+	// APIReader reads the resources from the API server without the cache of the manager,
+	// it's used for the resources which can be in any namespace. The client is used if it's not set.
+	APIReader client.Reader
+
+	subnetSyncs           subnetSyncTracker
+	clusterInfoResolvedAt time.Time
+	vpcSource             aws.VPCSource
 }
 
 //+kubebuilder:rbac:groups=networking.olm.openshift.io,resources=awsloadbalancercontrollers,verbs=get;list;watch;create;update;patch;delete
@@ -77,10 +104,18 @@ type AWSLoadBalancerControllerReconciler struct {
 //+kubebuilder:rbac:groups=networking.olm.openshift.io,resources=awsloadbalancercontrollers/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=services;secrets,namespace=system,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=configmaps,namespace=system,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=endpoints;pods,namespace=system,verbs=get;list;watch
 //+kubebuilder:rbac:groups="networking.k8s.io",resources=ingressclasses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses,verbs=get;list;watch
+//+kubebuilder:rbac:groups="elbv2.k8s.aws",resources=ingressclassparams,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups="config.openshift.io",resources=infrastructures,verbs=get;list;watch
 //+kubebuilder:rbac:groups="apps",resources=deployments,namespace=system,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="policy",resources=poddisruptionbudgets,namespace=system,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="networking.k8s.io",resources=networkpolicies,namespace=system,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="monitoring.coreos.com",resources=servicemonitors;prometheusrules,namespace=system,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;update;patch
 //+kubebuilder:rbac:groups="",resources=serviceaccounts,namespace=system,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,namespace=system,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
@@ -96,40 +131,101 @@ func (r *AWSLoadBalancerControllerReconciler) Reconcile(ctx context.Context, req
 		return ctrl.Result{}, fmt.Errorf("failed to get AWSLoadBalancerController %q: %w", req.Name, err)
 	}
 	if !exists {
+		forgetMetrics(req.Name)
 		return ctrl.Result{}, nil
 	}
 
+	// the deletion is handled first as the cluster information
+	// is only needed to clean up the subnet tags
 	if lbController.DeletionTimestamp != nil {
-		logger.Info("AWSLoadBalancerController is going to be deleted. Skipping Reconcile")
-		return ctrl.Result{}, nil
+		logger.Info("AWSLoadBalancerController is going to be deleted. Cleaning up")
+		result, err := r.finalize(ctx, lbController)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to clean up AWSLoadBalancerController %q: %w", req.Name, err)
+		}
+		return result, nil
+	}
+
+	infraConfig, requeue, err := r.ensureClusterInfo(ctx, lbController, lbController.Spec.VPCID == "")
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to resolve cluster information for AWSLoadBalancerController %q: %w", req.Name, err)
+	}
+	if requeue {
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if err := r.ensureFinalizer(ctx, lbController); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to add finalizer to AWSLoadBalancerController %q: %w", req.Name, err)
+	}
 
+	// multiple instances cannot share the same ingress class as they would reconcile the same Ingresses
+	conflicting, err := r.conflictingIngressClassOwner(ctx, lbController)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to verify IngressClass of AWSLoadBalancerController %q is not used by other instances: %w", req.Name, err)
+	}
+	if err := r.updateStatusConditions(ctx, lbController, ingressClassConditions(lbController.Spec.IngressClass, conflicting, lbController.Generation)...); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status of AWSLoadBalancerController %q: %w", req.Name, err)
+	}
+	if conflicting != "" {
+		r.eventf(lbController, corev1.EventTypeWarning, ingressClassConflictEventReason, "IngressClass %q is already used by the AWSLoadBalancerController %q", lbController.Spec.IngressClass, conflicting)
+		logger.Info("(Retrying) IngressClass is already used by another instance", "ingressclass", lbController.Spec.IngressClass, "instance", conflicting)
+		return ctrl.Result{RequeueAfter: classConflictReEnqueueDuration}, nil
+	}
+
+	// multiple instances cannot share the same load balancer class as they would reconcile the same Services
+	conflicting, err = r.conflictingLoadBalancerClassOwner(ctx, lbController)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to verify LoadBalancerClass of AWSLoadBalancerController %q is not used by other instances: %w", req.Name, err)
+	}
+	if err := r.updateStatusConditions(ctx, lbController, loadBalancerClassConditions(loadBalancerClass(lbController), conflicting, lbController.Generation)...); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status of AWSLoadBalancerController %q: %w", req.Name, err)
+	}
+	if conflicting != "" {
+		r.eventf(lbController, corev1.EventTypeWarning, loadBalancerClassConflictEventReason, "LoadBalancerClass %q is already used by the AWSLoadBalancerController %q", loadBalancerClass(lbController), conflicting)
+		logger.Info("(Retrying) LoadBalancerClass is already used by another instance", "loadbalancerclass", loadBalancerClass(lbController), "instance", conflicting)
+		return ctrl.Result{RequeueAfter: classConflictReEnqueueDuration}, nil
 	}
 
 	servingSecretName := fmt.Sprintf("%s-serving-%s", controllerResourcePrefix, lbController.Name)
 
-	// if the processed subnets have not yet been written into the status or if the tagging policy has changed then update the subnets
-	if lbController.Status.Subnets == nil || (lbController.Spec.SubnetTagging != lbController.Status.Subnets.SubnetTagging) {
+	// update the subnets if the processed subnets have not yet been written into the status, if the tagging policy has changed
+	// or if the resync interval elapsed since the last synchronization
+	if r.subnetsNeedSync(lbController, time.Now()) {
 		internalSubnets, publicSubnets, untaggedSubnets, taggedSubnets, err := r.tagSubnets(ctx, lbController)
 		if err != nil {
+			if statusErr := r.updateStatusConditions(ctx, lbController, subnetsConditions(err, lbController.Generation)...); statusErr != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to update status of AWSLoadBalancerController %q: %w", req.Name, statusErr)
+			}
+			// the requests are already retried by the AWS client, the reconciliation
+			// is requeued with the backoff of the queue to give time to the AWS API to recover
+			if aws.IsTransient(err) {
+				logger.Info("(Retrying) AWS API requests failed temporarily while tagging the subnets", "error", err)
+				return ctrl.Result{Requeue: true}, nil
+			}
+			r.eventf(lbController, corev1.EventTypeWarning, subnetsSyncFailedEventReason, "Failed to discover and tag the subnets: %v", err)
 			return ctrl.Result{}, fmt.Errorf("failed to update subnets: %w", err)
 		}
 		err = r.updateStatusSubnets(ctx, lbController, internalSubnets, publicSubnets, untaggedSubnets, taggedSubnets, lbController.Spec.SubnetTagging)
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to update AWSLoadBalancerController %q status with subnets: %w", req.Name, err)
 		}
+		r.subnetSyncs.synced(lbController.Name, time.Now())
+		reportSubnets(lbController.Name, internalSubnets, publicSubnets, taggedSubnets, untaggedSubnets)
 		// reload the resource after updating the status
 		lbController, _, err = r.getAWSLoadBalancerController(ctx, req.Name)
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to get AWSLoadBalancerController %q: %w", req.Name, err)
 		}
+		if err := r.updateStatusConditions(ctx, lbController, subnetsConditions(nil, lbController.Generation)...); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update status of AWSLoadBalancerController %q: %w", req.Name, err)
+		}
 	}
 
-	infraConfig := &configv1.Infrastructure{}
-	if err := r.Client.Get(ctx, types.NamespacedName{Name: clusterInfrastructureName}, infraConfig); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to get infrastructure %q: %w", clusterInfrastructureName, err)
-	}
 	platformStatus := infraConfig.Status.PlatformStatus
 
+	if err := r.ensureIngressClassParams(ctx, lbController); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to ensure IngressClassParams for AWSLoadBalancerController %q: %w", req.Name, err)
+	}
 	if err := r.ensureIngressClass(ctx, lbController); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to ensure default IngressClass for AWSLoadBalancerController %q: %v", req.Name, err)
 	}
@@ -162,6 +258,8 @@ func (r *AWSLoadBalancerControllerReconciler) Reconcile(ctx context.Context, req
 		return ctrl.Result{}, fmt.Errorf("failed to verify credentials secret %q for AWSLoadBalancerController %q has been provisioned: %w", credSecretNsName.Name, req.Name, err)
 	}
 
+	reportCredentialsSecret(lbController.Name, secretProvisioned)
+
 	// updating CR status
 	if err := r.updateControllerStatus(ctx, lbController, nil, credSecretNsName.Name, secretProvisioned); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to update status of AWSLoadBalancerController %q: %w", req.Name, err)
@@ -169,6 +267,7 @@ func (r *AWSLoadBalancerControllerReconciler) Reconcile(ctx context.Context, req
 
 	// re-enqueue if secret is not provisioned
 	if !secretProvisioned {
+		r.eventf(lbController, corev1.EventTypeWarning, credentialsSecretNotProvisionedEventReason, "Waiting for the credentials secret %q to be provisioned", credSecretNsName.Name)
 		// retrying after delay to ensure secret provisioning.
 		logger.Info("(Retrying) failed to ensure secret from credentials request", "secret", credSecretNsName.Name)
 		return ctrl.Result{RequeueAfter: secretMissingReEnqueueDuration}, nil
@@ -202,11 +301,31 @@ func (r *AWSLoadBalancerControllerReconciler) Reconcile(ctx context.Context, req
 		return ctrl.Result{}, fmt.Errorf("failed to ensure Deployment for AWSLoadbalancerController %q: %w", req.Name, err)
 	}
 
+	err = r.ensurePodDisruptionBudget(ctx, r.Namespace, lbController, deployment)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to ensure PodDisruptionBudget for AWSLoadBalancerController %q: %w", req.Name, err)
+	}
+
+	err = r.ensureNetworkPolicies(ctx, r.Namespace, lbController, deployment)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to ensure NetworkPolicies for AWSLoadBalancerController %q: %w", req.Name, err)
+	}
+
 	service, err := r.ensureService(ctx, r.Namespace, lbController, servingSecretName, deployment)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to ensure service for AWSLoadBalancerController %q: %w", req.Name, err)
 	}
 
+	err = r.ensureServiceMonitor(ctx, r.Namespace, lbController, service)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to ensure ServiceMonitor for AWSLoadBalancerController %q: %w", req.Name, err)
+	}
+
+	err = r.ensurePrometheusRule(ctx, r.Namespace, lbController, service.Name)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to ensure PrometheusRule for AWSLoadBalancerController %q: %w", req.Name, err)
+	}
+
 	err = r.ensureWebhooks(ctx, lbController, service)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to ensure webhooks for AWSLoadBalancerController %q: %w", req.Name, err)
@@ -215,7 +334,8 @@ func (r *AWSLoadBalancerControllerReconciler) Reconcile(ctx context.Context, req
 	if err := r.updateControllerStatus(ctx, lbController, deployment, credSecretNsName.Name, secretProvisioned); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to update status of AWSLoadBalancerController %q: %w", req.Name, err)
 	}
-	return ctrl.Result{}, nil
+	lastSuccessfulReconcile.observe(lbController.Name)
+	return ctrl.Result{RequeueAfter: r.subnetResyncAfter(lbController.Name, time.Now())}, nil
 }
 
 func (r *AWSLoadBalancerControllerReconciler) getAWSLoadBalancerController(ctx context.Context, name string) (*albo.AWSLoadBalancerController, bool, error) {
@@ -246,43 +366,60 @@ func (r *AWSLoadBalancerControllerReconciler) getConfigMap(ctx context.Context,
 // BuildManagedController returns the controller builder with all the watches set up.
 func (r *AWSLoadBalancerControllerReconciler) BuildManagedController(mgr ctrl.Manager) *builder.Builder {
 	bldr := ctrl.NewControllerManagedBy(mgr).
-		For(&albo.AWSLoadBalancerController{}, builder.WithPredicates(predicate.NewPredicateFuncs(hasName(controllerName)))).
+		For(&albo.AWSLoadBalancerController{}).
 		Owns(&cco.CredentialsRequest{}).
+		Owns(&networkingv1.IngressClass{}).
+		Owns(&elbv1beta1.IngressClassParams{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&rbacv1.ClusterRoleBinding{}).
 		Owns(&rbacv1.Role{}).
 		Owns(&rbacv1.RoleBinding{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&networkingv1.NetworkPolicy{}).
 		Owns(&corev1.Service{}).
 		Owns(&arv1.ValidatingWebhookConfiguration{}).
 		Owns(&arv1.MutatingWebhookConfiguration{})
 
-	clusterALBCInstance := func(ctx context.Context, o client.Object) []reconcile.Request {
-		return []reconcile.Request{
-			{
+	mgrClient := mgr.GetClient()
+	allALBCInstances := func(ctx context.Context, o client.Object) []reconcile.Request {
+		var controllers albo.AWSLoadBalancerControllerList
+		if err := mgrClient.List(ctx, &controllers); err != nil {
+			log.FromContext(ctx).Error(err, "failed to list AWSLoadBalancerControllers")
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(controllers.Items))
+		for _, c := range controllers.Items {
+			requests = append(requests, reconcile.Request{
 				NamespacedName: types.NamespacedName{
-					Name: controllerName,
+					Name: c.Name,
 				},
-			},
+			})
 		}
+		return requests
 	}
 
 	if r.TrustedCAConfigMapName != "" {
-		// Requeue the only (cluster) instance of AWSLoadBalancerController
+		// Requeue all the instances of AWSLoadBalancerController
 		// so that the main reconciliation loop can detect the changes in the trusted CA configmap's contents
 		// and redeploy the controller if needed.
 		// The change detection is achieved using the annotation which contains the configmap's contents hash.
 		// The hash is recalculated at each reconciliation and put in the controller deployment's template pod spec
 		// leading to a rollout in case of a change.
 		bldr = bldr.Watches(&corev1.ConfigMap{},
-			handler.EnqueueRequestsFromMapFunc(clusterALBCInstance),
+			handler.EnqueueRequestsFromMapFunc(allALBCInstances),
 			builder.WithPredicates(predicate.And(
 				predicate.NewPredicateFuncs(inNamespace(r.Namespace))),
 				predicate.NewPredicateFuncs(hasName(r.TrustedCAConfigMapName))))
 	}
+	// Requeue all the instances when an instance is created or deleted
+	// so that the instance handling the unlabelled TargetGroupBindings updates its webhooks
+	bldr = bldr.Watches(&albo.AWSLoadBalancerController{},
+		handler.EnqueueRequestsFromMapFunc(allALBCInstances),
+		builder.WithPredicates(instanceAddedOrRemoved()))
 	// Watch Infrastructure object to detect changes in AWS user tags
 	bldr = bldr.Watches(&configv1.Infrastructure{},
-		handler.EnqueueRequestsFromMapFunc(clusterALBCInstance),
+		handler.EnqueueRequestsFromMapFunc(allALBCInstances),
 		builder.WithPredicates(
 			predicate.NewPredicateFuncs(hasName(clusterInfrastructureName))))
 
@@ -307,3 +444,16 @@ func inNamespace(namespace string) func(o client.Object) bool {
 		return o.GetNamespace() == namespace
 	}
 }
+
+// instanceAddedOrRemoved returns a predicate which accepts the creation of an instance,
+// the start of its deletion and its removal.
+func instanceAddedOrRemoved() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return true },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetDeletionTimestamp() == nil && e.ObjectNew.GetDeletionTimestamp() != nil
+		},
+		DeleteFunc:  func(event.DeleteEvent) bool { return true },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
@@ -68,6 +68,7 @@ func (r *AWSLoadBalancerControllerReconciler) ensureCredentialsRequest(ctx conte
 		if err := r.createCredentialsRequest(ctx, desired); err != nil {
 			return nil, fmt.Errorf("failed to create credentials request %s: %w", desired.Name, err)
 		}
+		r.eventf(controller, corev1.EventTypeNormal, credentialsRequestCreatedEventReason, "Created CredentialsRequest %s", credReq)
 		found, created, err := r.currentCredentialsRequest(ctx, credReq)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get new credentials request %q: %w", credReq.Name, err)
@@ -83,6 +84,7 @@ func (r *AWSLoadBalancerControllerReconciler) ensureCredentialsRequest(ctx conte
 		return nil, fmt.Errorf("failed to update credentials request %q: %w", credReq.Name, err)
 	}
 	if gotUpdated {
+		r.eventf(controller, corev1.EventTypeNormal, credentialsRequestUpdatedEventReason, "Updated CredentialsRequest %s", credReq)
 		found, updated, err := r.currentCredentialsRequest(ctx, credReq)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get updated credentials request %q: %w", credReq.Name, err)
@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/record"
 
 	cco "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
 
@@ -53,45 +54,52 @@ func TestEnsureCredentialsRequest(t *testing.T) {
 		name            string
 		existingObjects []runtime.Object
 		expectedEvents  []test.Event
-		errExpected     bool
+		// expectedRecorded are the events recorded on the AWSLoadBalancerController
+		expectedRecorded []string
+		errExpected      bool
 	}{
 		{
-			name:            "Initial bootstrap",
-			existingObjects: make([]runtime.Object, 0),
-			expectedEvents:  []test.Event{addEvent},
-			errExpected:     false,
+			name:             "Initial bootstrap",
+			existingObjects:  make([]runtime.Object, 0),
+			expectedEvents:   []test.Event{addEvent},
+			expectedRecorded: []string{"Normal CredentialsRequestCreated Created CredentialsRequest openshift-cloud-credential-operator/aws-load-balancer-controller-cluster"},
+			errExpected:      false,
 		},
 		{
 			name: "Change in Credential Request. ProviderSpec",
 			existingObjects: []runtime.Object{
 				testCredentialsRequestProviderSpecDiff(),
 			},
-			expectedEvents: []test.Event{modifyEvent},
-			errExpected:    false,
+			expectedEvents:   []test.Event{modifyEvent},
+			expectedRecorded: []string{"Normal CredentialsRequestUpdated Updated CredentialsRequest openshift-cloud-credential-operator/aws-load-balancer-controller-cluster"},
+			errExpected:      false,
 		},
 		{
 			name: "Change in Credential Request. SecretName",
 			existingObjects: []runtime.Object{
 				testCredentialsRequestSecretNameDiff(),
 			},
-			expectedEvents: []test.Event{modifyEvent},
-			errExpected:    false,
+			expectedEvents:   []test.Event{modifyEvent},
+			expectedRecorded: []string{"Normal CredentialsRequestUpdated Updated CredentialsRequest openshift-cloud-credential-operator/aws-load-balancer-controller-cluster"},
+			errExpected:      false,
 		},
 		{
 			name: "Change in Credential Request. SecretNamespace",
 			existingObjects: []runtime.Object{
 				testCredentialsRequestSecretNsDiff(),
 			},
-			expectedEvents: []test.Event{modifyEvent},
-			errExpected:    false,
+			expectedEvents:   []test.Event{modifyEvent},
+			expectedRecorded: []string{"Normal CredentialsRequestUpdated Updated CredentialsRequest openshift-cloud-credential-operator/aws-load-balancer-controller-cluster"},
+			errExpected:      false,
 		},
 		{
 			name: "Change in Credential Request. ServiceAccounts",
 			existingObjects: []runtime.Object{
 				testCredentialsRequestSADiff(),
 			},
-			expectedEvents: []test.Event{modifyEvent},
-			errExpected:    false,
+			expectedEvents:   []test.Event{modifyEvent},
+			expectedRecorded: []string{"Normal CredentialsRequestUpdated Updated CredentialsRequest openshift-cloud-credential-operator/aws-load-balancer-controller-cluster"},
+			errExpected:      false,
 		},
 		{
 			name: "No change in Credential Request",
@@ -108,12 +116,14 @@ func TestEnsureCredentialsRequest(t *testing.T) {
 			cl := fake.NewClientBuilder().WithScheme(test.Scheme).
 				WithRuntimeObjects(tc.existingObjects...).
 				Build()
+			recorder := record.NewFakeRecorder(10)
 
 			r := &AWSLoadBalancerControllerReconciler{
 				Client:    cl,
 				Namespace: test.OperatorNamespace,
 				Image:     test.OperandImage,
 				Scheme:    test.Scheme,
+				Recorder:  recorder,
 			}
 
 			c := test.NewEventCollector(t, cl, managedTypesList, len(tc.expectedEvents))
@@ -149,6 +159,9 @@ func TestEnsureCredentialsRequest(t *testing.T) {
 			if diff := cmp.Diff(idxExpectedEvents, idxCollectedEvents); diff != "" {
 				t.Fatalf("found diff between expected and collected events: %s", diff)
 			}
+			if diff := cmp.Diff(tc.expectedRecorded, recordedEvents(recorder)); diff != "" {
+				t.Errorf("unexpected recorded events (-want +got):\n%s", diff)
+			}
 		})
 	}
 }
@@ -10,7 +10,9 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
@@ -69,6 +71,12 @@ const (
 	defaultCABundleKey = "ca-bundle.crt"
 	// all capabilities in the pod security context
 	allCapabilities = "ALL"
+	// defaultCPURequest is the CPU request of the controller container used when no resources are specified.
+	defaultCPURequest = "50m"
+	// defaultMemoryRequest is the memory request of the controller container used when no resources are specified.
+	defaultMemoryRequest = "128Mi"
+	// defaultLeaderElectionID is the default name of the lock used by the controller for the leader election.
+	defaultLeaderElectionID = "aws-load-balancer-controller-leader"
 )
 
 func (r *AWSLoadBalancerControllerReconciler) ensureDeployment(ctx context.Context, sa *corev1.ServiceAccount, crSecretName, servingSecretName string, controller *albo.AWSLoadBalancerController, platformStatus *configv1.PlatformStatus, trustCAConfigMap *corev1.ConfigMap) (*appsv1.Deployment, error) {
@@ -104,6 +112,7 @@ func (r *AWSLoadBalancerControllerReconciler) ensureDeployment(ctx context.Conte
 		if err != nil {
 			return nil, fmt.Errorf("failed to create deployment %s: %w", deploymentName, err)
 		}
+		r.eventf(controller, corev1.EventTypeNormal, deploymentCreatedEventReason, "Created deployment %s/%s", r.Namespace, deploymentName)
 		_, current, err = r.currentDeployment(ctx, deploymentName, r.Namespace)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get new deployment %s: %w", deploymentName, err)
@@ -115,6 +124,7 @@ func (r *AWSLoadBalancerControllerReconciler) ensureDeployment(ctx context.Conte
 		return nil, fmt.Errorf("failed to update existing deployment: %w", err)
 	}
 	if updated {
+		r.eventf(controller, corev1.EventTypeNormal, deploymentUpdatedEventReason, "Updated deployment %s/%s, rolling out the controller pods", r.Namespace, deploymentName)
 		_, current, err = r.currentDeployment(ctx, deploymentName, r.Namespace)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get existing deployment: %w", err)
@@ -146,9 +156,10 @@ func (r *AWSLoadBalancerControllerReconciler) desiredDeployment(name, credential
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{
 						{
-							Name:  awsLoadBalancerControllerContainerName,
-							Image: r.Image,
-							Args:  desiredContainerArgs(controller, r.ClusterName, r.VPCID, platformStatus),
+							Name:      awsLoadBalancerControllerContainerName,
+							Image:     r.Image,
+							Args:      desiredContainerArgs(controller, r.ClusterName, r.controllerVPCID(controller), platformStatus),
+							Resources: desiredContainerResources(controller),
 							Env: append([]corev1.EnvVar{
 								{
 									Name:  awsRegionEnvVarName,
@@ -233,8 +244,14 @@ func (r *AWSLoadBalancerControllerReconciler) desiredDeployment(name, credential
 			},
 		},
 	}
-	if controller.Spec.Config != nil && controller.Spec.Config.Replicas != 0 {
-		d.Spec.Replicas = ptr.To[int32](controller.Spec.Config.Replicas)
+	if controller.Spec.Config != nil {
+		if controller.Spec.Config.Replicas != 0 {
+			d.Spec.Replicas = ptr.To[int32](controller.Spec.Config.Replicas)
+		}
+		d.Spec.Template.Spec.NodeSelector = controller.Spec.Config.NodeSelector
+		d.Spec.Template.Spec.Tolerations = controller.Spec.Config.Tolerations
+		d.Spec.Template.Spec.Affinity = controller.Spec.Config.Affinity
+		d.Spec.Template.Spec.TopologySpreadConstraints = controller.Spec.Config.TopologySpreadConstraints
 	}
 	if trustedCAConfigMapName != "" {
 		if trustedCAConfigMapHash != "" {
@@ -263,6 +280,53 @@ func (r *AWSLoadBalancerControllerReconciler) desiredDeployment(name, credential
 	return d
 }
 
+// desiredContainerResources returns the resource requirements of the controller container.
+// The requirements from the controller's spec are used if set, the default requests are used otherwise.
+// The requirements from the spec are defaulted like the API server does so that they compare equal
+// to the requirements of the existing deployment.
+func desiredContainerResources(controller *albo.AWSLoadBalancerController) corev1.ResourceRequirements {
+	if controller.Spec.Config != nil && controller.Spec.Config.Resources != nil {
+		return defaultedResources(*controller.Spec.Config.Resources.DeepCopy())
+	}
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(defaultCPURequest),
+			corev1.ResourceMemory: resource.MustParse(defaultMemoryRequest),
+		},
+	}
+}
+
+// defaultedResources returns the given resource requirements with the requests defaulted to the limits
+// for the resources which only have a limit, the empty lists are dropped as they are not persisted.
+func defaultedResources(resources corev1.ResourceRequirements) corev1.ResourceRequirements {
+	for name, limit := range resources.Limits {
+		if _, ok := resources.Requests[name]; ok {
+			continue
+		}
+		if resources.Requests == nil {
+			resources.Requests = corev1.ResourceList{}
+		}
+		resources.Requests[name] = limit.DeepCopy()
+	}
+	if len(resources.Requests) == 0 {
+		resources.Requests = nil
+	}
+	if len(resources.Limits) == 0 {
+		resources.Limits = nil
+	}
+	return resources
+}
+
+// leaderElectionID returns the ID of the lock used for the leader election by the replicas of the given instance.
+// The default instance uses the controller's default ID, other instances get an ID suffixed with their name
+// so that they don't compete for the same lock.
+func leaderElectionID(controller *albo.AWSLoadBalancerController) string {
+	if controller.Name == controllerName {
+		return defaultLeaderElectionID
+	}
+	return fmt.Sprintf("%s-%s", defaultLeaderElectionID, controller.Name)
+}
+
 func desiredContainerArgs(controller *albo.AWSLoadBalancerController, clusterName, vpcID string, platformStatus *configv1.PlatformStatus) []string {
 	var args []string
 	args = append(args, fmt.Sprintf("--webhook-cert-dir=%s", webhookTLSDir))
@@ -278,6 +342,10 @@ func desiredContainerArgs(controller *albo.AWSLoadBalancerController, clusterNam
 	args = append(args, "--disable-ingress-group-name-annotation")
 	if controller.Spec.Config != nil && controller.Spec.Config.Replicas > 1 {
 		args = append(args, "--enable-leader-election")
+		// the default instance keeps the controller's default ID
+		if controller.Name != controllerName {
+			args = append(args, fmt.Sprintf("--leader-election-id=%s", leaderElectionID(controller)))
+		}
 	}
 	enabledAddons := make(map[albo.AWSAddon]struct{})
 	for _, a := range controller.Spec.EnabledAddons {
@@ -299,11 +367,29 @@ func desiredContainerArgs(controller *albo.AWSLoadBalancerController, clusterNam
 		args = append(args, "--enable-wafv2=false")
 	}
 	args = append(args, fmt.Sprintf("--ingress-class=%s", controller.Spec.IngressClass))
-	args = append(args, "--feature-gates=EnableIPTargetType=false")
+	args = append(args, fmt.Sprintf("--load-balancer-class=%s", loadBalancerClass(controller)))
+	args = append(args, fmt.Sprintf("--feature-gates=%s", desiredFeatureGates(controller)))
 	sort.Strings(args)
 	return args
 }
 
+// desiredFeatureGates returns the value of the controller's feature gates argument.
+// The IP target type is disabled unless it's explicitly enabled in the controller's spec.
+func desiredFeatureGates(controller *albo.AWSLoadBalancerController) string {
+	gates := map[albo.FeatureGateName]bool{
+		albo.FeatureGateEnableIPTargetType: false,
+	}
+	for _, fg := range controller.Spec.FeatureGates {
+		gates[fg.Name] = fg.Enabled
+	}
+	var values []string
+	for name, enabled := range gates {
+		values = append(values, fmt.Sprintf("%s=%t", name, enabled))
+	}
+	sort.Strings(values)
+	return strings.Join(values, ",")
+}
+
 func (r *AWSLoadBalancerControllerReconciler) currentDeployment(ctx context.Context, name string, namespace string) (bool, *appsv1.Deployment, error) {
 	var deployment appsv1.Deployment
 	err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &deployment)
@@ -381,6 +467,14 @@ func (r *AWSLoadBalancerControllerReconciler) updateDeployment(ctx context.Conte
 		outdated = true
 	}
 
+	if hasPodSchedulingChanged(updated.Spec.Template.Spec, desired.Spec.Template.Spec) {
+		updated.Spec.Template.Spec.NodeSelector = desired.Spec.Template.Spec.NodeSelector
+		updated.Spec.Template.Spec.Tolerations = desired.Spec.Template.Spec.Tolerations
+		updated.Spec.Template.Spec.Affinity = desired.Spec.Template.Spec.Affinity
+		updated.Spec.Template.Spec.TopologySpreadConstraints = desired.Spec.Template.Spec.TopologySpreadConstraints
+		outdated = true
+	}
+
 	if outdated {
 		err := r.Update(ctx, updated)
 		if err != nil {
@@ -414,6 +508,24 @@ func haveVolumesChanged(current []corev1.Volume, desired []corev1.Volume) bool {
 	return false
 }
 
+// hasPodSchedulingChanged checks if the scheduling constraints of the current pod spec differ from the desired ones.
+// Nil and empty values are considered equal.
+func hasPodSchedulingChanged(current, desired corev1.PodSpec) bool {
+	if !equality.Semantic.DeepEqual(current.NodeSelector, desired.NodeSelector) {
+		return true
+	}
+	if !equality.Semantic.DeepEqual(current.Tolerations, desired.Tolerations) {
+		return true
+	}
+	if !equality.Semantic.DeepEqual(current.Affinity, desired.Affinity) {
+		return true
+	}
+	if !equality.Semantic.DeepEqual(current.TopologySpreadConstraints, desired.TopologySpreadConstraints) {
+		return true
+	}
+	return false
+}
+
 func hasContainerChanged(current, desired corev1.Container) bool {
 	if current.Image != desired.Image {
 		return true
@@ -426,6 +538,10 @@ func hasContainerChanged(current, desired corev1.Container) bool {
 		return true
 	}
 
+	if !equality.Semantic.DeepEqual(current.Resources, desired.Resources) {
+		return true
+	}
+
 	if len(current.Env) != len(desired.Env) {
 		return true
 	}
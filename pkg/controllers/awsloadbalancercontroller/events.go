@@ -0,0 +1,38 @@
+package awsloadbalancercontroller
+
+import (
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+)
+
+// the reasons of the events recorded on the AWSLoadBalancerController
+const (
+	subnetsTaggedEventReason                   = "SubnetsTagged"
+	subnetsUntaggedEventReason                 = "SubnetsUntagged"
+	subnetsSyncFailedEventReason               = "SubnetsSyncFailed"
+	credentialsRequestCreatedEventReason       = "CredentialsRequestCreated"
+	credentialsRequestUpdatedEventReason       = "CredentialsRequestUpdated"
+	credentialsSecretNotProvisionedEventReason = "CredentialsSecretNotProvisioned"
+	ingressClassCreatedEventReason             = "IngressClassCreated"
+	ingressClassDeletedEventReason             = "IngressClassDeleted"
+	ingressClassConflictEventReason            = "IngressClassConflict"
+	ingressClassUpdatedEventReason             = "IngressClassUpdated"
+	loadBalancerClassConflictEventReason       = "LoadBalancerClassConflict"
+	ingressClassParamsCreatedEventReason       = "IngressClassParamsCreated"
+	ingressClassParamsUpdatedEventReason       = "IngressClassParamsUpdated"
+	ingressClassParamsDeletedEventReason       = "IngressClassParamsDeleted"
+	ingressClassParamsConflictEventReason      = "IngressClassParamsConflict"
+	deploymentCreatedEventReason               = "DeploymentCreated"
+	deploymentUpdatedEventReason               = "DeploymentUpdated"
+	deletionBlockedEventReason                 = "DeletionBlocked"
+	cleanupFailedEventReason                   = "CleanupFailed"
+	clusterInfoResolutionFailedEventReason     = "ClusterInfoResolutionFailed"
+)
+
+// eventf records an event on the given controller.
+// The event is dropped if the reconciler has no event recorder.
+func (r *AWSLoadBalancerControllerReconciler) eventf(controller *albo.AWSLoadBalancerController, eventType, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(controller, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,44 @@
+package awsloadbalancercontroller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+)
+
+func TestEventf(t *testing.T) {
+	controller := &albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	recorder := record.NewFakeRecorder(10)
+	r := &AWSLoadBalancerControllerReconciler{Recorder: recorder}
+	r.eventf(controller, corev1.EventTypeNormal, subnetsTaggedEventReason, "Tagged subnets %v", []string{"subnet-1"})
+	if diff := cmp.Diff([]string{"Normal SubnetsTagged Tagged subnets [subnet-1]"}, recordedEvents(recorder)); diff != "" {
+		t.Errorf("unexpected events (-want +got):\n%s", diff)
+	}
+
+	// the events are dropped when no recorder is set
+	defer func() {
+		if p := recover(); p != nil {
+			t.Errorf("expected the event to be dropped without a recorder, got panic: %v", p)
+		}
+	}()
+	r = &AWSLoadBalancerControllerReconciler{}
+	r.eventf(controller, corev1.EventTypeNormal, subnetsTaggedEventReason, "Tagged subnets %v", []string{"subnet-1"})
+}
+
+// recordedEvents drains the events recorded by the given fake recorder.
+func recordedEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
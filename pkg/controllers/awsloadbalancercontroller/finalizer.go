@@ -0,0 +1,247 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+)
+
+const (
+	// cleanupFinalizer is the finalizer which holds the AWSLoadBalancerController
+	// until the resources created by the operator outside of the owner references are removed.
+	cleanupFinalizer = "networking.olm.openshift.io/aws-load-balancer-controller-cleanup"
+	// serviceResourcesFinalizer is the finalizer set by the controller on the services
+	// for which it provisioned load balancers.
+	serviceResourcesFinalizer = "service.k8s.aws/resources"
+	// legacyIngressClassAnnotation is the annotation which sets the class of the ingresses
+	// created before the IngressClass resource was introduced.
+	legacyIngressClassAnnotation = "kubernetes.io/ingress.class"
+	// cleanupBlockedReEnqueueDuration is the delay to re-enqueue when the deletion is blocked
+	// by the resources still served by the controller.
+	cleanupBlockedReEnqueueDuration = time.Second * 30
+)
+
+// ensureFinalizer adds the cleanup finalizer to the controller if it's not present yet.
+func (r *AWSLoadBalancerControllerReconciler) ensureFinalizer(ctx context.Context, controller *albo.AWSLoadBalancerController) error {
+	if controllerutil.ContainsFinalizer(controller, cleanupFinalizer) {
+		return nil
+	}
+	controllerutil.AddFinalizer(controller, cleanupFinalizer)
+	return r.Update(ctx, controller)
+}
+
+// finalize releases the controller being deleted once the cleanup is done.
+// The cleanup waits for the Ingresses and Services served by the controller to be removed
+// as the controller has to deprovision their load balancers. It then removes the subnet tags
+// added by the operator and the IngressClass created for the controller.
+func (r *AWSLoadBalancerControllerReconciler) finalize(ctx context.Context, controller *albo.AWSLoadBalancerController) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(controller, cleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	blocking, err := r.blockingResources(ctx, controller)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list resources blocking the deletion: %w", err)
+	}
+	if err := r.updateStatusConditions(ctx, controller, deletionConditions(blocking, controller.Generation)...); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %w", err)
+	}
+	if len(blocking) > 0 {
+		r.eventf(controller, corev1.EventTypeNormal, deletionBlockedEventReason, "Waiting for the resources served by the controller to be removed: %s", strings.Join(blocking, ", "))
+		logger.Info("(Retrying) deletion is blocked by resources served by the controller", "resources", blocking)
+		return ctrl.Result{RequeueAfter: cleanupBlockedReEnqueueDuration}, nil
+	}
+
+	sharesSubnetTags, err := r.subnetTagsUsedByOtherInstances(ctx, controller)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list other AWSLoadBalancerControllers: %w", err)
+	}
+	if controller.Spec.SubnetTagging == albo.AutoSubnetTaggingPolicy && !sharesSubnetTags {
+		// the VPC is only needed to validate the subnets selected in the spec
+		_, requeue, err := r.ensureClusterInfo(ctx, controller, controller.Spec.VPCID == "" && controller.Spec.Subnets != nil)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to resolve cluster information: %w", err)
+		}
+		if requeue {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		untagged, err := r.untagSubnets(ctx, controller)
+		if err != nil {
+			r.eventf(controller, corev1.EventTypeWarning, cleanupFailedEventReason, "Failed to remove the subnet tags: %v", err)
+			return ctrl.Result{}, fmt.Errorf("failed to remove subnet tags: %w", err)
+		}
+		logger.Info("removed tags from subnets", "subnets", untagged)
+	}
+
+	if err := r.deleteIngressClass(ctx, controller); err != nil {
+		r.eventf(controller, corev1.EventTypeWarning, cleanupFailedEventReason, "Failed to delete the IngressClass: %v", err)
+		return ctrl.Result{}, err
+	}
+
+	controllerutil.RemoveFinalizer(controller, cleanupFinalizer)
+	if err := r.Update(ctx, controller); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+	r.subnetSyncs.forget(controller.Name)
+	forgetMetrics(controller.Name)
+	return ctrl.Result{}, nil
+}
+
+// blockingResources returns the references of the Ingresses and Services
+// for which the controller still manages load balancers.
+// The resources are listed from the API server as they can be in any namespace
+// while the cache of the manager is limited to the operator namespace.
+func (r *AWSLoadBalancerControllerReconciler) blockingResources(ctx context.Context, controller *albo.AWSLoadBalancerController) ([]string, error) {
+	var blocking []string
+
+	// the ingresses are served by another instance if the ingress class is in conflict
+	conflicting, err := r.conflictingIngressClassOwner(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+	if conflicting == "" {
+		var ingresses networkingv1.IngressList
+		if err := r.apiReader().List(ctx, &ingresses); err != nil {
+			return nil, err
+		}
+		for _, ing := range ingresses.Items {
+			if isIngressServedByController(&ing, controller) {
+				blocking = append(blocking, fmt.Sprintf("ingress/%s/%s", ing.Namespace, ing.Name))
+			}
+		}
+	}
+
+	// the services are served by another instance if the load balancer class is in conflict
+	conflicting, err = r.conflictingLoadBalancerClassOwner(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+	if conflicting == "" {
+		var services corev1.ServiceList
+		if err := r.apiReader().List(ctx, &services); err != nil {
+			return nil, err
+		}
+		for _, svc := range services.Items {
+			if isServiceServedByController(&svc, controller) {
+				blocking = append(blocking, fmt.Sprintf("service/%s/%s", svc.Namespace, svc.Name))
+			}
+		}
+	}
+
+	sort.Strings(blocking)
+	return blocking, nil
+}
+
+// isIngressServedByController checks whether the given ingress belongs to the ingress class of the controller.
+// The ingresses without ingress class name are matched by their legacy ingress class annotation,
+// the ingresses without any class are served by the controller if its ingress class is the default one.
+func isIngressServedByController(ing *networkingv1.Ingress, controller *albo.AWSLoadBalancerController) bool {
+	if ing.Spec.IngressClassName != nil {
+		return *ing.Spec.IngressClassName == controller.Spec.IngressClass
+	}
+	if class, ok := ing.Annotations[legacyIngressClassAnnotation]; ok {
+		return class == controller.Spec.IngressClass
+	}
+	return controller.Spec.DefaultIngressClass == albo.DefaultIngressClassEnabled
+}
+
+// isServiceServedByController checks whether the controller has provisioned a load balancer for the given service.
+// The services of another load balancer class are served by another instance, the services without
+// a load balancer class are served by any instance.
+func isServiceServedByController(svc *corev1.Service, controller *albo.AWSLoadBalancerController) bool {
+	if !controllerutil.ContainsFinalizer(svc, serviceResourcesFinalizer) {
+		return false
+	}
+	return svc.Spec.LoadBalancerClass == nil || *svc.Spec.LoadBalancerClass == loadBalancerClass(controller)
+}
+
+// apiReader returns the reader which lists the resources from the API server,
+// the client of the reconciler is used if no reader is set.
+func (r *AWSLoadBalancerControllerReconciler) apiReader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
+	}
+	return r.APIReader
+}
+
+// subnetTagsUsedByOtherInstances checks whether any other instance relies on the subnet tags added by the operator.
+func (r *AWSLoadBalancerControllerReconciler) subnetTagsUsedByOtherInstances(ctx context.Context, controller *albo.AWSLoadBalancerController) (bool, error) {
+	var controllers albo.AWSLoadBalancerControllerList
+	if err := r.List(ctx, &controllers); err != nil {
+		return false, err
+	}
+	for _, other := range controllers.Items {
+		if other.Name != controller.Name && other.DeletionTimestamp == nil && other.Spec.SubnetTagging == albo.AutoSubnetTaggingPolicy {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// deleteIngressClass deletes the IngressClass created for the controller.
+// The IngressClasses which were not created by the operator are left untouched.
+func (r *AWSLoadBalancerControllerReconciler) deleteIngressClass(ctx context.Context, controller *albo.AWSLoadBalancerController) error {
+	name := controller.Status.IngressClass
+	if name == "" {
+		return nil
+	}
+
+	var ingressClass networkingv1.IngressClass
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, &ingressClass); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get IngressClass %q: %w", name, err)
+	}
+	if !metav1.IsControlledBy(&ingressClass, controller) {
+		return nil
+	}
+	if err := r.Delete(ctx, &ingressClass); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete IngressClass %q: %w", name, err)
+	}
+	r.eventf(controller, corev1.EventTypeNormal, ingressClassDeletedEventReason, "Deleted IngressClass %q", name)
+	return nil
+}
+
+func deletionConditions(blocking []string, generation int64) []metav1.Condition {
+	if len(blocking) > 0 {
+		return []metav1.Condition{
+			{
+				Type:               DeletionBlockedCondition,
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: generation,
+				Reason:             "LoadBalancersInUse",
+				Message:            fmt.Sprintf("Waiting for the resources served by the controller to be removed: %s", strings.Join(blocking, ", ")),
+			},
+		}
+	}
+	return []metav1.Condition{
+		{
+			Type:               DeletionBlockedCondition,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             "NoLoadBalancersInUse",
+			Message:            "No resources served by the controller remain",
+		},
+	}
+}
@@ -0,0 +1,426 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	configv1 "github.com/openshift/api/config/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+
+	"github.com/google/go-cmp/cmp"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+	"github.com/openshift/aws-load-balancer-operator/pkg/aws"
+	"github.com/openshift/aws-load-balancer-operator/pkg/utils"
+	"github.com/openshift/aws-load-balancer-operator/pkg/utils/test"
+)
+
+func TestEnsureFinalizer(t *testing.T) {
+	controller := &albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	testClient := fake.NewClientBuilder().WithScheme(test.Scheme).WithObjects(controller).Build()
+	r := &AWSLoadBalancerControllerReconciler{
+		Client: testClient,
+		Scheme: test.Scheme,
+	}
+
+	if err := r.ensureFinalizer(context.Background(), controller); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var updated albo.AWSLoadBalancerController
+	if err := testClient.Get(context.Background(), types.NamespacedName{Name: "cluster"}, &updated); err != nil {
+		t.Fatalf("failed to get controller: %v", err)
+	}
+	if !controllerutil.ContainsFinalizer(&updated, cleanupFinalizer) {
+		t.Errorf("expected finalizer %q, got %v", cleanupFinalizer, updated.Finalizers)
+	}
+}
+
+func TestFinalize(t *testing.T) {
+	deletingController := func(name, ingressClass string, taggingPolicy albo.SubnetTaggingPolicy) *albo.AWSLoadBalancerController {
+		return &albo.AWSLoadBalancerController{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				UID:               types.UID(name),
+				Finalizers:        []string{cleanupFinalizer},
+				DeletionTimestamp: ptr.To(metav1.Now()),
+			},
+			Spec: albo.AWSLoadBalancerControllerSpec{
+				SubnetTagging: taggingPolicy,
+				IngressClass:  ingressClass,
+			},
+			Status: albo.AWSLoadBalancerControllerStatus{
+				IngressClass: ingressClass,
+			},
+		}
+	}
+	ownedIngressClass := func(name string, owner *albo.AWSLoadBalancerController) *networkingv1.IngressClass {
+		ic := desiredIngressClass(name, false, false)
+		if owner != nil {
+			_ = controllerutil.SetControllerReference(owner, ic, test.Scheme)
+		}
+		return ic
+	}
+	testIngress := func(name, ingressClass string) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace"},
+			Spec:       networkingv1.IngressSpec{IngressClassName: ptr.To(ingressClass)},
+		}
+	}
+	testService := func(name string, finalizers ...string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace", Finalizers: finalizers},
+		}
+	}
+
+	testServiceWithClass := func(name, loadBalancerClass string, finalizers ...string) *corev1.Service {
+		svc := testService(name, finalizers...)
+		svc.Spec.LoadBalancerClass = ptr.To(loadBalancerClass)
+		return svc
+	}
+
+	for _, tc := range []struct {
+		name                        string
+		controller                  *albo.AWSLoadBalancerController
+		existingObjects             []client.Object
+		currentSubnets              []ec2types.Subnet
+		newEC2ClientErr             error
+		expectedErr                 string
+		expectedRequeue             bool
+		expectedBlocked             string
+		expectedRemoveTagOperations []string
+		expectedIngressClassDeleted bool
+		expectedEvents              []string
+	}{
+		{
+			name:       "blocked by ingress of the controller's class",
+			controller: deletingController("cluster", "alb", albo.AutoSubnetTaggingPolicy),
+			existingObjects: []client.Object{
+				testIngress("echoserver", "alb"),
+				testIngress("other", "openshift-default"),
+			},
+			expectedBlocked: "Waiting for the resources served by the controller to be removed: ingress/test-namespace/echoserver",
+			expectedEvents:  []string{"Normal DeletionBlocked Waiting for the resources served by the controller to be removed: ingress/test-namespace/echoserver"},
+		},
+		{
+			name:       "blocked by service with load balancer",
+			controller: deletingController("cluster", "alb", albo.AutoSubnetTaggingPolicy),
+			existingObjects: []client.Object{
+				testService("echoserver", serviceResourcesFinalizer),
+				testService("other"),
+			},
+			expectedBlocked: "Waiting for the resources served by the controller to be removed: service/test-namespace/echoserver",
+			expectedEvents:  []string{"Normal DeletionBlocked Waiting for the resources served by the controller to be removed: service/test-namespace/echoserver"},
+		},
+		{
+			name:       "not blocked by service of another load balancer class",
+			controller: deletingController("cluster", "alb", albo.ManualSubnetTaggingPolicy),
+			existingObjects: []client.Object{
+				testServiceWithClass("echoserver", "service.k8s.aws/nlb-internal", serviceResourcesFinalizer),
+			},
+			expectedIngressClassDeleted: true,
+			expectedEvents:              []string{`Normal IngressClassDeleted Deleted IngressClass "alb"`},
+		},
+		{
+			name:       "blocked by service of the controller's load balancer class",
+			controller: deletingController("cluster", "alb", albo.AutoSubnetTaggingPolicy),
+			existingObjects: []client.Object{
+				testServiceWithClass("echoserver", "service.k8s.aws/nlb", serviceResourcesFinalizer),
+			},
+			expectedBlocked: "Waiting for the resources served by the controller to be removed: service/test-namespace/echoserver",
+			expectedEvents:  []string{"Normal DeletionBlocked Waiting for the resources served by the controller to be removed: service/test-namespace/echoserver"},
+		},
+		{
+			name:       "not blocked by service when load balancer class is used by an older instance",
+			controller: deletingController("internal", "alb-internal", albo.ManualSubnetTaggingPolicy),
+			existingObjects: []client.Object{
+				&albo.AWSLoadBalancerController{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+					Spec:       albo.AWSLoadBalancerControllerSpec{IngressClass: "alb"},
+				},
+				testServiceWithClass("echoserver", "service.k8s.aws/nlb", serviceResourcesFinalizer),
+			},
+			expectedIngressClassDeleted: true,
+			expectedEvents:              []string{`Normal IngressClassDeleted Deleted IngressClass "alb-internal"`},
+		},
+		{
+			name:       "auto tagging, subnet tags and ingress class removed",
+			controller: deletingController("cluster", "alb", albo.AutoSubnetTaggingPolicy),
+			currentSubnets: []ec2types.Subnet{
+				testSubnet("subnet-1", publicELBTagKey, tagKeyALBOTagged),
+				testSubnet("subnet-2", internalELBTagKey),
+				testSubnet("subnet-3", publicELBTagKey),
+			},
+			expectedRemoveTagOperations: []string{"subnet-1"},
+			expectedIngressClassDeleted: true,
+			expectedEvents: []string{
+				"Normal SubnetsUntagged Removed tag kubernetes.io/role/elb from subnets [subnet-1]",
+				`Normal IngressClassDeleted Deleted IngressClass "alb"`,
+			},
+		},
+		{
+			name:       "manual tagging, subnet tags kept",
+			controller: deletingController("cluster", "alb", albo.ManualSubnetTaggingPolicy),
+			currentSubnets: []ec2types.Subnet{
+				testSubnet("subnet-1", publicELBTagKey, tagKeyALBOTagged),
+			},
+			expectedIngressClassDeleted: true,
+			expectedEvents:              []string{`Normal IngressClassDeleted Deleted IngressClass "alb"`},
+		},
+		{
+			name:       "subnet tags used by another instance",
+			controller: deletingController("internal", "alb-internal", albo.AutoSubnetTaggingPolicy),
+			existingObjects: []client.Object{
+				&albo.AWSLoadBalancerController{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+					Spec:       albo.AWSLoadBalancerControllerSpec{SubnetTagging: albo.AutoSubnetTaggingPolicy, IngressClass: "alb"},
+				},
+			},
+			currentSubnets: []ec2types.Subnet{
+				testSubnet("subnet-1", publicELBTagKey, tagKeyALBOTagged),
+			},
+			expectedIngressClassDeleted: true,
+			expectedEvents:              []string{`Normal IngressClassDeleted Deleted IngressClass "alb-internal"`},
+		},
+		{
+			name:                        "manual tagging, released when cluster information cannot be resolved",
+			controller:                  deletingController("cluster", "alb", albo.ManualSubnetTaggingPolicy),
+			newEC2ClientErr:             fmt.Errorf("no credentials"),
+			expectedIngressClassDeleted: true,
+			expectedEvents:              []string{`Normal IngressClassDeleted Deleted IngressClass "alb"`},
+		},
+		{
+			name:            "auto tagging, cluster information cannot be resolved",
+			controller:      deletingController("cluster", "alb", albo.AutoSubnetTaggingPolicy),
+			newEC2ClientErr: fmt.Errorf("no credentials"),
+			expectedErr:     `failed to resolve cluster information: failed to create EC2 client for region "us-east-1": no credentials`,
+			expectedEvents:  []string{`Warning ClusterInfoResolutionFailed Failed to resolve the cluster information: failed to create EC2 client for region "us-east-1": no credentials`},
+		},
+		{
+			name:            "auto tagging, cluster information failed temporarily",
+			controller:      deletingController("cluster", "alb", albo.AutoSubnetTaggingPolicy),
+			newEC2ClientErr: &aws.RequestError{Operation: "DescribeVpcs", Code: "RequestTimeout", Transient: true, Err: fmt.Errorf("request timed out")},
+			expectedRequeue: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			// the status conditions are reported in the metrics of the controller
+			t.Cleanup(func() { forgetMetrics(tc.controller.Name) })
+			existingObjects := append([]client.Object{
+				tc.controller,
+				ownedIngressClass(tc.controller.Spec.IngressClass, tc.controller),
+				&configv1.Infrastructure{
+					ObjectMeta: metav1.ObjectMeta{Name: clusterInfrastructureName},
+					Status: configv1.InfrastructureStatus{
+						InfrastructureName: "test-cluster",
+						PlatformStatus: &configv1.PlatformStatus{
+							Type: configv1.AWSPlatformType,
+							AWS:  &configv1.AWSPlatformStatus{Region: "us-east-1"},
+						},
+					},
+				},
+			}, tc.existingObjects...)
+			testClient := fake.NewClientBuilder().
+				WithScheme(test.Scheme).
+				WithObjects(existingObjects...).
+				WithStatusSubresource(&albo.AWSLoadBalancerController{}).
+				Build()
+			ec2Client := &testEC2Client{
+				t:         t,
+				subnets:   tc.currentSubnets,
+				clusterID: "test-cluster",
+			}
+			recorder := record.NewFakeRecorder(10)
+			r := &AWSLoadBalancerControllerReconciler{
+				Client:      testClient,
+				Scheme:      test.Scheme,
+				EC2Client:   ec2Client,
+				ClusterName: "test-cluster",
+				Recorder:    recorder,
+			}
+			if tc.newEC2ClientErr != nil {
+				r.NewEC2Client = func(_ context.Context, _ string) (aws.EC2Client, error) {
+					return nil, tc.newEC2ClientErr
+				}
+			}
+
+			result, err := r.finalize(ctx, tc.controller)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("expected error %q, got %v", tc.expectedErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedEvents, recordedEvents(recorder)); diff != "" {
+				t.Errorf("unexpected events (-want +got):\n%s", diff)
+			}
+			if result.Requeue != tc.expectedRequeue {
+				t.Errorf("expected requeue to be %t, got %t", tc.expectedRequeue, result.Requeue)
+			}
+			if tc.expectedErr != "" || tc.expectedRequeue {
+				var controller albo.AWSLoadBalancerController
+				if err := testClient.Get(ctx, types.NamespacedName{Name: tc.controller.Name}, &controller); err != nil {
+					t.Fatalf("failed to get controller: %v", err)
+				}
+				if !controllerutil.ContainsFinalizer(&controller, cleanupFinalizer) {
+					t.Errorf("expected finalizer to be kept until the subnet tags are removed")
+				}
+				return
+			}
+
+			var controller albo.AWSLoadBalancerController
+			err = testClient.Get(ctx, types.NamespacedName{Name: tc.controller.Name}, &controller)
+			if tc.expectedBlocked != "" {
+				if err != nil {
+					t.Fatalf("failed to get controller: %v", err)
+				}
+				if result.RequeueAfter != cleanupBlockedReEnqueueDuration {
+					t.Errorf("expected requeue after %v, got %v", cleanupBlockedReEnqueueDuration, result.RequeueAfter)
+				}
+				if !controllerutil.ContainsFinalizer(&controller, cleanupFinalizer) {
+					t.Errorf("expected finalizer to be kept while the deletion is blocked")
+				}
+				cond := meta.FindStatusCondition(controller.Status.Conditions, DeletionBlockedCondition)
+				if cond == nil || cond.Status != metav1.ConditionTrue {
+					t.Fatalf("expected condition %s to be true, got %v", DeletionBlockedCondition, cond)
+				}
+				if cond.Message != tc.expectedBlocked {
+					t.Errorf("unexpected condition message, expected %q, got %q", tc.expectedBlocked, cond.Message)
+				}
+				return
+			}
+
+			// the fake client removes the object once the last finalizer is gone
+			if !errors.IsNotFound(err) {
+				t.Errorf("expected controller to be released, got %v", err)
+			}
+			if !utils.EqualStrings(tc.expectedRemoveTagOperations, ec2Client.untaggedResources) {
+				t.Errorf("expected subnets %v to have been untagged, instead got %v", tc.expectedRemoveTagOperations, ec2Client.untaggedResources)
+			}
+			err = testClient.Get(ctx, types.NamespacedName{Name: tc.controller.Spec.IngressClass}, &networkingv1.IngressClass{})
+			if tc.expectedIngressClassDeleted && !errors.IsNotFound(err) {
+				t.Errorf("expected ingress class %q to be deleted, got %v", tc.controller.Spec.IngressClass, err)
+			}
+		})
+	}
+}
+
+func TestDeleteIngressClassNotOwned(t *testing.T) {
+	controller := &albo.AWSLoadBalancerController{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "cluster"},
+		Status:     albo.AWSLoadBalancerControllerStatus{IngressClass: "alb"},
+	}
+	testClient := fake.NewClientBuilder().WithScheme(test.Scheme).WithObjects(desiredIngressClass("alb", false, false)).Build()
+	r := &AWSLoadBalancerControllerReconciler{
+		Client: testClient,
+		Scheme: test.Scheme,
+	}
+
+	if err := r.deleteIngressClass(context.Background(), controller); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := testClient.Get(context.Background(), types.NamespacedName{Name: "alb"}, &networkingv1.IngressClass{}); err != nil {
+		t.Errorf("expected ingress class not created by the operator to be kept, got %v", err)
+	}
+}
+
+func TestBlockingResources(t *testing.T) {
+	testIngress := func(namespace, name string, ingressClass *string, annotations map[string]string) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Annotations: annotations},
+			Spec:       networkingv1.IngressSpec{IngressClassName: ingressClass},
+		}
+	}
+	testService := func(namespace, name string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Finalizers: []string{serviceResourcesFinalizer}},
+		}
+	}
+	testController := func(defaultIngressClass albo.DefaultIngressClassPolicy) *albo.AWSLoadBalancerController {
+		return &albo.AWSLoadBalancerController{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec:       albo.AWSLoadBalancerControllerSpec{IngressClass: "alb", DefaultIngressClass: defaultIngressClass},
+		}
+	}
+
+	for _, tc := range []struct {
+		name             string
+		controller       *albo.AWSLoadBalancerController
+		workloads        []client.Object
+		expectedBlocking []string
+	}{
+		{
+			name:       "workloads outside of the operator namespace",
+			controller: testController(albo.DefaultIngressClassDisabled),
+			workloads: []client.Object{
+				testIngress("aws-load-balancer-operator", "ingress", ptr.To("alb"), nil),
+				testIngress("app-1", "ingress", ptr.To("alb"), nil),
+				testService("app-2", "service"),
+			},
+			expectedBlocking: []string{
+				"ingress/app-1/ingress",
+				"ingress/aws-load-balancer-operator/ingress",
+				"service/app-2/service",
+			},
+		},
+		{
+			name:       "ingresses with legacy ingress class annotation",
+			controller: testController(albo.DefaultIngressClassDisabled),
+			workloads: []client.Object{
+				testIngress("app", "annotated", nil, map[string]string{legacyIngressClassAnnotation: "alb"}),
+				testIngress("app", "other-annotated", nil, map[string]string{legacyIngressClassAnnotation: "openshift-default"}),
+				testIngress("app", "class-name-first", ptr.To("openshift-default"), map[string]string{legacyIngressClassAnnotation: "alb"}),
+			},
+			expectedBlocking: []string{"ingress/app/annotated"},
+		},
+		{
+			name:       "ingresses without class and default ingress class",
+			controller: testController(albo.DefaultIngressClassEnabled),
+			workloads: []client.Object{
+				testIngress("app", "no-class", nil, nil),
+				testIngress("app", "other-class", ptr.To("openshift-default"), nil),
+			},
+			expectedBlocking: []string{"ingress/app/no-class"},
+		},
+		{
+			name:       "ingresses without class and not default ingress class",
+			controller: testController(albo.DefaultIngressClassDisabled),
+			workloads: []client.Object{
+				testIngress("app", "no-class", nil, nil),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// the client only sees the operator namespace like the cache of the manager,
+			// the workloads are only found by the API reader
+			r := &AWSLoadBalancerControllerReconciler{
+				Client:    fake.NewClientBuilder().WithScheme(test.Scheme).WithObjects(tc.controller).Build(),
+				APIReader: fake.NewClientBuilder().WithScheme(test.Scheme).WithObjects(tc.workloads...).Build(),
+				Scheme:    test.Scheme,
+			}
+			blocking, err := r.blockingResources(context.Background(), tc.controller)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedBlocking, blocking); diff != "" {
+				t.Errorf("unexpected blocking resources (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
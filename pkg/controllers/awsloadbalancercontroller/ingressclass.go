@@ -4,10 +4,15 @@ import (
 	"context"
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
+	elbv1beta1 "sigs.k8s.io/aws-load-balancer-controller/apis/elbv2/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
@@ -15,45 +20,198 @@ import (
 
 const (
 	albIngressClassController = "ingress.k8s.aws/alb"
+	// ingressClassParamsKind is the kind of the resource referenced by the parameters of the IngressClass.
+	ingressClassParamsKind = "IngressClassParams"
 )
 
 // ensureIngressClass create the default IngressClass which is specified in the controller. This is required because the OpenShift router
 // reconciles any Ingress resource whose class is not defined or if the IngressClass does not have the spec.controllerName set.
 // Steps to ensure the IngressClass
-// 1. Check the status to see if the IngressClass already exists
-// 2. If the name matches then do nothing.
-// 3. If the name does not match then delete the existing IngressClass. Ignore if it doesn't exist.
-// 4. Create the new IngresClass with the correct controller name. If there is an AlreadyExists error, ignore it.
+// 1. If the name in the status does not match the spec then delete the IngressClass from the status. Ignore if it doesn't exist.
+// 2. Create the IngressClass with the correct controller name if it doesn't exist. If there is an AlreadyExists error, ignore it.
+// 3. Leave the IngressClass untouched if it was not created by the operator.
+// 4. Recreate the IngressClass if its controller name was changed, the controller name is immutable.
+// 5. Update the parameters and the default class annotation of the IngressClass if they changed.
 func (r *AWSLoadBalancerControllerReconciler) ensureIngressClass(ctx context.Context, controller *albo.AWSLoadBalancerController) error {
-
-	// if the current ingress class is the same then do nothing.
-	if controller.Status.IngressClass == controller.Spec.IngressClass {
-		return nil
-	}
-
 	// if the current ingress class name does not match then delete it.
-	if controller.Status.IngressClass != "" {
+	if controller.Status.IngressClass != "" && controller.Status.IngressClass != controller.Spec.IngressClass {
 		err := r.Delete(ctx, &networkingv1.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: controller.Status.IngressClass}})
 		if err != nil && !errors.IsNotFound(err) {
 			return fmt.Errorf("failed to delete existing IngressClass %q: %w", controller.Status.IngressClass, err)
 		}
+		if err == nil {
+			r.eventf(controller, corev1.EventTypeNormal, ingressClassDeletedEventReason, "Deleted IngressClass %q replaced by %q", controller.Status.IngressClass, controller.Spec.IngressClass)
+		}
 	}
 
-	ingressClass := desiredIngressClass(controller.Spec.IngressClass)
+	ingressClass := desiredIngressClass(controller.Spec.IngressClass, controller.Spec.IngressClassParams != nil, controller.Spec.DefaultIngressClass == albo.DefaultIngressClassEnabled)
 	err := controllerutil.SetControllerReference(controller, ingressClass, r.Scheme)
 	if err != nil {
 		return fmt.Errorf("failed to set owner reference on new IngressClass %q: %w", ingressClass.Name, err)
 	}
 
-	err = r.Create(ctx, ingressClass)
-	if err != nil && !errors.IsAlreadyExists(err) {
-		return fmt.Errorf("failed to create default IngressClass %s: %w", controller.Spec.IngressClass, err)
+	var current networkingv1.IngressClass
+	err = r.Get(ctx, types.NamespacedName{Name: ingressClass.Name}, &current)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get existing IngressClass %q: %w", ingressClass.Name, err)
+	}
+	if err != nil {
+		return r.createIngressClass(ctx, controller, ingressClass)
+	}
+
+	// the IngressClasses which were not created by the operator are left untouched
+	if !metav1.IsControlledBy(&current, controller) {
+		return nil
+	}
+
+	if current.Spec.Controller != ingressClass.Spec.Controller {
+		if err := r.Delete(ctx, &current); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete IngressClass %q with unexpected controller %q: %w", current.Name, current.Spec.Controller, err)
+		}
+		r.eventf(controller, corev1.EventTypeNormal, ingressClassDeletedEventReason, "Deleted IngressClass %q with unexpected controller %q", current.Name, current.Spec.Controller)
+		return r.createIngressClass(ctx, controller, ingressClass)
+	}
+
+	isDefault := current.Annotations[networkingv1.AnnotationIsDefaultIngressClass]
+	if equality.Semantic.DeepEqual(current.Spec.Parameters, ingressClass.Spec.Parameters) && isDefault == ingressClass.Annotations[networkingv1.AnnotationIsDefaultIngressClass] {
+		return nil
+	}
+	updated := current.DeepCopy()
+	updated.Spec.Parameters = ingressClass.Spec.Parameters
+	if value, ok := ingressClass.Annotations[networkingv1.AnnotationIsDefaultIngressClass]; ok {
+		if updated.Annotations == nil {
+			updated.Annotations = map[string]string{}
+		}
+		updated.Annotations[networkingv1.AnnotationIsDefaultIngressClass] = value
+	} else {
+		delete(updated.Annotations, networkingv1.AnnotationIsDefaultIngressClass)
+	}
+	if err := r.Update(ctx, updated); err != nil {
+		return fmt.Errorf("failed to update IngressClass %q: %w", ingressClass.Name, err)
+	}
+	r.eventf(controller, corev1.EventTypeNormal, ingressClassUpdatedEventReason, "Updated IngressClass %q", ingressClass.Name)
+	return nil
+}
+
+// createIngressClass creates the given IngressClass. An IngressClass which already exists is not an error.
+func (r *AWSLoadBalancerControllerReconciler) createIngressClass(ctx context.Context, controller *albo.AWSLoadBalancerController, ingressClass *networkingv1.IngressClass) error {
+	if err := r.Create(ctx, ingressClass); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to create default IngressClass %s: %w", ingressClass.Name, err)
+	}
+	r.eventf(controller, corev1.EventTypeNormal, ingressClassCreatedEventReason, "Created IngressClass %q", ingressClass.Name)
+	return nil
+}
+
+// ensureIngressClassParams ensures that the IngressClassParams of the controller exists and is up-to-date.
+// The IngressClassParams has the same name as the ingress class of the controller.
+// The IngressClassParams of the previous ingress class, or the current one if the spec doesn't have parameters anymore, is removed.
+// An IngressClassParams with the same name which was not created for the controller is left untouched and a warning event is recorded.
+func (r *AWSLoadBalancerControllerReconciler) ensureIngressClassParams(ctx context.Context, controller *albo.AWSLoadBalancerController) error {
+	if controller.Status.IngressClass != "" && controller.Status.IngressClass != controller.Spec.IngressClass {
+		if err := r.deleteIngressClassParams(ctx, controller, controller.Status.IngressClass); err != nil {
+			return err
+		}
+	}
+	if controller.Spec.IngressClassParams == nil {
+		return r.deleteIngressClassParams(ctx, controller, controller.Spec.IngressClass)
+	}
+
+	desired := desiredIngressClassParams(controller.Spec.IngressClass, controller.Spec.IngressClassParams)
+	if err := controllerutil.SetControllerReference(controller, desired, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner reference on desired IngressClassParams %q: %w", desired.Name, err)
+	}
+
+	var current elbv1beta1.IngressClassParams
+	err := r.Get(ctx, types.NamespacedName{Name: desired.Name}, &current)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get existing IngressClassParams %q: %w", desired.Name, err)
+	}
+	if err != nil {
+		if err := r.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create IngressClassParams %q: %w", desired.Name, err)
+		}
+		r.eventf(controller, corev1.EventTypeNormal, ingressClassParamsCreatedEventReason, "Created IngressClassParams %q", desired.Name)
+		return nil
+	}
+
+	// the IngressClassParams which were not created for the controller are left untouched
+	if !metav1.IsControlledBy(&current, controller) {
+		r.eventf(controller, corev1.EventTypeWarning, ingressClassParamsConflictEventReason, "IngressClassParams %q is not managed by the AWSLoadBalancerController %q and is left untouched", desired.Name, controller.Name)
+		return nil
+	}
+
+	if equality.Semantic.DeepEqual(current.Spec, desired.Spec) {
+		return nil
+	}
+	updated := current.DeepCopy()
+	updated.Spec = desired.Spec
+	if err := r.Update(ctx, updated); err != nil {
+		return fmt.Errorf("failed to update IngressClassParams %q: %w", desired.Name, err)
+	}
+	r.eventf(controller, corev1.EventTypeNormal, ingressClassParamsUpdatedEventReason, "Updated IngressClassParams %q", desired.Name)
+	return nil
+}
+
+// deleteIngressClassParams deletes the IngressClassParams with the given name if it was created for the controller.
+func (r *AWSLoadBalancerControllerReconciler) deleteIngressClassParams(ctx context.Context, controller *albo.AWSLoadBalancerController, name string) error {
+	var params elbv1beta1.IngressClassParams
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, &params); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get IngressClassParams %q: %w", name, err)
+	}
+	if !metav1.IsControlledBy(&params, controller) {
+		return nil
+	}
+	if err := r.Delete(ctx, &params); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete IngressClassParams %q: %w", name, err)
 	}
+	r.eventf(controller, corev1.EventTypeNormal, ingressClassParamsDeletedEventReason, "Deleted IngressClassParams %q", name)
 	return nil
 }
 
-func desiredIngressClass(name string) *networkingv1.IngressClass {
-	return &networkingv1.IngressClass{
+// conflictingIngressClassOwner returns the name of the instance which claimed the ingress class of the given instance first.
+// An empty string is returned if no other instance uses the same ingress class.
+func (r *AWSLoadBalancerControllerReconciler) conflictingIngressClassOwner(ctx context.Context, controller *albo.AWSLoadBalancerController) (string, error) {
+	return r.conflictingOwner(ctx, controller, func(other *albo.AWSLoadBalancerController) bool {
+		return other.Spec.IngressClass == controller.Spec.IngressClass
+	})
+}
+
+// conflictingOwner returns the name of the instance which claimed first a class shared with the given instance,
+// sharesClass tells whether another instance uses the same class as the given instance.
+// The instance created earliest owns the class, the name is used as a tie-breaker.
+// An empty string is returned if no other instance shares the class.
+func (r *AWSLoadBalancerControllerReconciler) conflictingOwner(ctx context.Context, controller *albo.AWSLoadBalancerController, sharesClass func(other *albo.AWSLoadBalancerController) bool) (string, error) {
+	var controllers albo.AWSLoadBalancerControllerList
+	if err := r.List(ctx, &controllers); err != nil {
+		return "", err
+	}
+	for i := range controllers.Items {
+		other := &controllers.Items[i]
+		if other.Name == controller.Name || other.DeletionTimestamp != nil || !sharesClass(other) {
+			continue
+		}
+		if other.CreationTimestamp.Before(&controller.CreationTimestamp) ||
+			(other.CreationTimestamp.Equal(&controller.CreationTimestamp) && other.Name < controller.Name) {
+			return other.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// desiredIngressClass returns the IngressClass with the given name.
+// The IngressClass references the IngressClassParams with the same name if withParams is true
+// and is annotated as the default IngressClass of the cluster if isDefault is true.
+func desiredIngressClass(name string, withParams, isDefault bool) *networkingv1.IngressClass {
+	ingressClass := &networkingv1.IngressClass{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: name,
 		},
@@ -61,4 +219,44 @@ func desiredIngressClass(name string) *networkingv1.IngressClass {
 			Controller: albIngressClassController,
 		},
 	}
+	if isDefault {
+		ingressClass.Annotations = map[string]string{networkingv1.AnnotationIsDefaultIngressClass: "true"}
+	}
+	if withParams {
+		ingressClass.Spec.Parameters = &networkingv1.IngressClassParametersReference{
+			APIGroup: ptr.To[string](elbv1beta1.GroupVersion.Group),
+			Kind:     ingressClassParamsKind,
+			Name:     name,
+		}
+	}
+	return ingressClass
+}
+
+// desiredIngressClassParams returns the IngressClassParams with the given name built from the given parameters.
+func desiredIngressClassParams(name string, params *albo.AWSLoadBalancerIngressClassParams) *elbv1beta1.IngressClassParams {
+	spec := elbv1beta1.IngressClassParamsSpec{
+		NamespaceSelector: params.NamespaceSelector.DeepCopy(),
+	}
+	if params.Scheme != "" {
+		spec.Scheme = ptr.To[elbv1beta1.LoadBalancerScheme](elbv1beta1.LoadBalancerScheme(params.Scheme))
+	}
+	if params.GroupName != "" {
+		spec.Group = &elbv1beta1.IngressGroup{Name: params.GroupName}
+	}
+	if params.IPAddressType != "" {
+		spec.IPAddressType = ptr.To[elbv1beta1.IPAddressType](elbv1beta1.IPAddressType(params.IPAddressType))
+	}
+	for _, tag := range params.Tags {
+		spec.Tags = append(spec.Tags, elbv1beta1.Tag{Key: tag.Key, Value: tag.Value})
+	}
+	for _, attribute := range params.LoadBalancerAttributes {
+		spec.LoadBalancerAttributes = append(spec.LoadBalancerAttributes, elbv1beta1.Attribute{Key: attribute.Key, Value: attribute.Value})
+	}
+
+	return &elbv1beta1.IngressClassParams{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: spec,
+	}
 }
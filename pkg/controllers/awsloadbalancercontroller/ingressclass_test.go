@@ -3,12 +3,18 @@ package awsloadbalancercontroller
 import (
 	"context"
 	"testing"
+	"time"
 
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/ptr"
+
+	elbv1beta1 "sigs.k8s.io/aws-load-balancer-controller/apis/elbv2/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -17,13 +23,31 @@ import (
 )
 
 func TestDesiredIngressClass(t *testing.T) {
-	ic := desiredIngressClass("test")
+	ic := desiredIngressClass("test", false, false)
 	if ic.Name != "test" {
 		t.Errorf("unexpected name in desired ingress class, expected %q, got %q", "test", ic.Name)
 	}
 	if ic.Spec.Controller != albIngressClassController {
 		t.Errorf("unexpected controller in desired ingress class, expected %q, got %q", albIngressClassController, ic.Spec.Controller)
 	}
+	if ic.Spec.Parameters != nil {
+		t.Errorf("unexpected parameters in desired ingress class: %v", ic.Spec.Parameters)
+	}
+
+	ic = desiredIngressClass("test", false, true)
+	if diff := cmp.Diff(map[string]string{"ingressclass.kubernetes.io/is-default-class": "true"}, ic.Annotations); diff != "" {
+		t.Errorf("unexpected annotations in desired default ingress class (-want +got):\n%s", diff)
+	}
+
+	ic = desiredIngressClass("test", true, false)
+	expectedParameters := &networkingv1.IngressClassParametersReference{
+		APIGroup: ptr.To[string]("elbv2.k8s.aws"),
+		Kind:     "IngressClassParams",
+		Name:     "test",
+	}
+	if diff := cmp.Diff(expectedParameters, ic.Spec.Parameters); diff != "" {
+		t.Errorf("unexpected parameters in desired ingress class (-want +got):\n%s", diff)
+	}
 }
 
 func TestEnsureIngressClass(t *testing.T) {
@@ -31,22 +55,106 @@ func TestEnsureIngressClass(t *testing.T) {
 		name                 string
 		existingIngressClass *networkingv1.IngressClass
 		ingressClassName     string
+		statusIngressClass   string
+		ingressClassParams   *albo.AWSLoadBalancerIngressClassParams
+		defaultIngressClass  albo.DefaultIngressClassPolicy
 		deletedIngressClass  bool
+		expectedParameters   *networkingv1.IngressClassParametersReference
+		expectedAnnotations  map[string]string
+		expectedEvents       []string
 	}{
 		{
 			name:             "no existing ingress class",
 			ingressClassName: "new",
+			expectedEvents:   []string{`Normal IngressClassCreated Created IngressClass "new"`},
 		},
 		{
 			name:                 "existing ingress class",
-			existingIngressClass: desiredIngressClass("old"),
+			existingIngressClass: desiredIngressClass("old", false, false),
 			ingressClassName:     "new",
 			deletedIngressClass:  true,
+			expectedEvents: []string{
+				`Normal IngressClassDeleted Deleted IngressClass "old" replaced by "new"`,
+				`Normal IngressClassCreated Created IngressClass "new"`,
+			},
 		},
 		{
 			name:                 "existing ingress class, name no change",
-			existingIngressClass: desiredIngressClass("old"),
+			existingIngressClass: desiredIngressClass("old", false, false),
+			ingressClassName:     "old",
+		},
+		{
+			name:               "no existing ingress class, with parameters",
+			ingressClassName:   "new",
+			ingressClassParams: &albo.AWSLoadBalancerIngressClassParams{Scheme: albo.IngressSchemeInternal},
+			expectedParameters: desiredIngressClass("new", true, false).Spec.Parameters,
+			expectedEvents:     []string{`Normal IngressClassCreated Created IngressClass "new"`},
+		},
+		{
+			name:                 "existing ingress class, parameters added",
+			existingIngressClass: testOwnedIngressClass("old", false, false),
+			ingressClassName:     "old",
+			ingressClassParams:   &albo.AWSLoadBalancerIngressClassParams{Scheme: albo.IngressSchemeInternal},
+			expectedParameters:   desiredIngressClass("old", true, false).Spec.Parameters,
+			expectedEvents:       []string{`Normal IngressClassUpdated Updated IngressClass "old"`},
+		},
+		{
+			name:                 "existing ingress class, parameters removed",
+			existingIngressClass: testOwnedIngressClass("old", true, false),
+			ingressClassName:     "old",
+			expectedEvents:       []string{`Normal IngressClassUpdated Updated IngressClass "old"`},
+		},
+		{
+			name:               "ingress class deleted",
+			ingressClassName:   "old",
+			statusIngressClass: "old",
+			expectedEvents:     []string{`Normal IngressClassCreated Created IngressClass "old"`},
+		},
+		{
+			name: "existing ingress class, controller modified",
+			existingIngressClass: func() *networkingv1.IngressClass {
+				ic := testOwnedIngressClass("old", false, false)
+				ic.Spec.Controller = "example.org/other"
+				return ic
+			}(),
+			ingressClassName: "old",
+			expectedEvents: []string{
+				`Normal IngressClassDeleted Deleted IngressClass "old" with unexpected controller "example.org/other"`,
+				`Normal IngressClassCreated Created IngressClass "old"`,
+			},
+		},
+		{
+			name:                 "existing ingress class, default class enabled",
+			existingIngressClass: testOwnedIngressClass("old", false, false),
+			ingressClassName:     "old",
+			defaultIngressClass:  albo.DefaultIngressClassEnabled,
+			expectedAnnotations:  map[string]string{"ingressclass.kubernetes.io/is-default-class": "true"},
+			expectedEvents:       []string{`Normal IngressClassUpdated Updated IngressClass "old"`},
+		},
+		{
+			name: "existing ingress class, default class disabled",
+			existingIngressClass: func() *networkingv1.IngressClass {
+				ic := testOwnedIngressClass("old", false, true)
+				ic.Annotations["example.org/team"] = "web"
+				return ic
+			}(),
+			ingressClassName:    "old",
+			defaultIngressClass: albo.DefaultIngressClassDisabled,
+			expectedAnnotations: map[string]string{"example.org/team": "web"},
+			expectedEvents:      []string{`Normal IngressClassUpdated Updated IngressClass "old"`},
+		},
+		{
+			name:                 "existing ingress class, default class no change",
+			existingIngressClass: testOwnedIngressClass("old", false, true),
+			ingressClassName:     "old",
+			defaultIngressClass:  albo.DefaultIngressClassEnabled,
+			expectedAnnotations:  map[string]string{"ingressclass.kubernetes.io/is-default-class": "true"},
+		},
+		{
+			name:                 "existing ingress class not created by the operator",
+			existingIngressClass: desiredIngressClass("old", false, false),
 			ingressClassName:     "old",
+			ingressClassParams:   &albo.AWSLoadBalancerIngressClassParams{Scheme: albo.IngressSchemeInternal},
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
@@ -57,17 +165,24 @@ func TestEnsureIngressClass(t *testing.T) {
 			controller := &albo.AWSLoadBalancerController{
 				ObjectMeta: metav1.ObjectMeta{Name: "test"},
 				Spec: albo.AWSLoadBalancerControllerSpec{
-					IngressClass: tc.ingressClassName,
+					IngressClass:        tc.ingressClassName,
+					IngressClassParams:  tc.ingressClassParams,
+					DefaultIngressClass: tc.defaultIngressClass,
 				},
 			}
 			if tc.existingIngressClass != nil {
 				controller.Status.IngressClass = tc.existingIngressClass.Name
 			}
+			if tc.statusIngressClass != "" {
+				controller.Status.IngressClass = tc.statusIngressClass
+			}
 			existingObjects = append(existingObjects, controller)
 			testClient := fake.NewClientBuilder().WithScheme(test.Scheme).WithObjects(existingObjects...).Build()
+			recorder := record.NewFakeRecorder(10)
 			r := &AWSLoadBalancerControllerReconciler{
-				Scheme: test.Scheme,
-				Client: testClient,
+				Scheme:   test.Scheme,
+				Client:   testClient,
+				Recorder: recorder,
 			}
 			err := r.ensureIngressClass(context.Background(), controller)
 			if err != nil {
@@ -82,6 +197,12 @@ func TestEnsureIngressClass(t *testing.T) {
 			if ingressClass.Spec.Controller != albIngressClassController {
 				t.Errorf("IngressClass does not have correct controller name, expected %q, got %q", albIngressClassController, ingressClass.Spec.Controller)
 			}
+			if diff := cmp.Diff(tc.expectedParameters, ingressClass.Spec.Parameters); diff != "" {
+				t.Errorf("unexpected IngressClass parameters (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedAnnotations, ingressClass.Annotations); diff != "" {
+				t.Errorf("unexpected IngressClass annotations (-want +got):\n%s", diff)
+			}
 			if tc.deletedIngressClass {
 				var ic networkingv1.IngressClass
 				err = r.Get(context.Background(), types.NamespacedName{Name: tc.existingIngressClass.Name}, &ic)
@@ -93,6 +214,273 @@ func TestEnsureIngressClass(t *testing.T) {
 					t.Errorf("existing ingress class %q was not deleted", tc.existingIngressClass.Name)
 				}
 			}
+			if diff := cmp.Diff(tc.expectedEvents, recordedEvents(recorder)); diff != "" {
+				t.Errorf("unexpected events (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDesiredIngressClassParams(t *testing.T) {
+	params := desiredIngressClassParams("alb", &albo.AWSLoadBalancerIngressClassParams{
+		Scheme:    albo.IngressSchemeInternetFacing,
+		GroupName: "shared",
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"example.org/team": "web"},
+		},
+		IPAddressType:          albo.IngressIPAddressTypeDualStack,
+		Tags:                   []albo.AWSResourceTag{{Key: "example.org/owner", Value: "web"}},
+		LoadBalancerAttributes: []albo.AWSLoadBalancerAttribute{{Key: "idle_timeout.timeout_seconds", Value: "120"}},
+	})
+
+	expected := elbv1beta1.IngressClassParamsSpec{
+		Scheme: ptr.To[elbv1beta1.LoadBalancerScheme](elbv1beta1.LoadBalancerSchemeInternetFacing),
+		Group:  &elbv1beta1.IngressGroup{Name: "shared"},
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"example.org/team": "web"},
+		},
+		IPAddressType:          ptr.To[elbv1beta1.IPAddressType](elbv1beta1.IPAddressTypeDualStack),
+		Tags:                   []elbv1beta1.Tag{{Key: "example.org/owner", Value: "web"}},
+		LoadBalancerAttributes: []elbv1beta1.Attribute{{Key: "idle_timeout.timeout_seconds", Value: "120"}},
+	}
+	if params.Name != "alb" {
+		t.Errorf("unexpected name in desired ingress class params, expected %q, got %q", "alb", params.Name)
+	}
+	if diff := cmp.Diff(expected, params.Spec); diff != "" {
+		t.Errorf("unexpected spec in desired ingress class params (-want +got):\n%s", diff)
+	}
+
+	params = desiredIngressClassParams("alb", &albo.AWSLoadBalancerIngressClassParams{})
+	if diff := cmp.Diff(elbv1beta1.IngressClassParamsSpec{}, params.Spec); diff != "" {
+		t.Errorf("unexpected spec in desired ingress class params without settings (-want +got):\n%s", diff)
+	}
+}
+
+func TestEnsureIngressClassParams(t *testing.T) {
+	internal := &albo.AWSLoadBalancerIngressClassParams{Scheme: albo.IngressSchemeInternal}
+	internetFacing := &albo.AWSLoadBalancerIngressClassParams{Scheme: albo.IngressSchemeInternetFacing}
+
+	for _, tc := range []struct {
+		name               string
+		existingObjects    []client.Object
+		ingressClassName   string
+		statusIngressClass string
+		ingressClassParams *albo.AWSLoadBalancerIngressClassParams
+		expectedParams     []string
+		expectedEvents     []string
+	}{
+		{
+			name:               "no existing ingress class params",
+			ingressClassName:   "alb",
+			ingressClassParams: internal,
+			expectedParams:     []string{"alb"},
+			expectedEvents:     []string{`Normal IngressClassParamsCreated Created IngressClassParams "alb"`},
+		},
+		{
+			name:               "existing ingress class params, settings modified",
+			existingObjects:    []client.Object{testOwnedIngressClassParams("alb", internetFacing)},
+			ingressClassName:   "alb",
+			statusIngressClass: "alb",
+			ingressClassParams: internal,
+			expectedParams:     []string{"alb"},
+			expectedEvents:     []string{`Normal IngressClassParamsUpdated Updated IngressClassParams "alb"`},
+		},
+		{
+			name:               "existing ingress class params, no change",
+			existingObjects:    []client.Object{testOwnedIngressClassParams("alb", internal)},
+			ingressClassName:   "alb",
+			statusIngressClass: "alb",
+			ingressClassParams: internal,
+			expectedParams:     []string{"alb"},
+		},
+		{
+			name:               "ingress class params unset",
+			existingObjects:    []client.Object{testOwnedIngressClassParams("alb", internal)},
+			ingressClassName:   "alb",
+			statusIngressClass: "alb",
+			expectedEvents:     []string{`Normal IngressClassParamsDeleted Deleted IngressClassParams "alb"`},
+		},
+		{
+			name:               "ingress class renamed",
+			existingObjects:    []client.Object{testOwnedIngressClassParams("old", internal)},
+			ingressClassName:   "new",
+			statusIngressClass: "old",
+			ingressClassParams: internal,
+			expectedParams:     []string{"new"},
+			expectedEvents: []string{
+				`Normal IngressClassParamsDeleted Deleted IngressClassParams "old"`,
+				`Normal IngressClassParamsCreated Created IngressClassParams "new"`,
+			},
+		},
+		{
+			name: "ingress class params not created by the operator",
+			existingObjects: []client.Object{
+				&elbv1beta1.IngressClassParams{ObjectMeta: metav1.ObjectMeta{Name: "alb"}},
+			},
+			ingressClassName:   "alb",
+			statusIngressClass: "alb",
+			expectedParams:     []string{"alb"},
+		},
+		{
+			name: "ingress class params with same name not created by the operator",
+			existingObjects: []client.Object{
+				&elbv1beta1.IngressClassParams{
+					ObjectMeta: metav1.ObjectMeta{Name: "alb"},
+					Spec:       elbv1beta1.IngressClassParamsSpec{Group: &elbv1beta1.IngressGroup{Name: "foreign"}},
+				},
+			},
+			ingressClassName:   "alb",
+			statusIngressClass: "alb",
+			ingressClassParams: internal,
+			expectedParams:     []string{"alb"},
+			expectedEvents:     []string{`Warning IngressClassParamsConflict IngressClassParams "alb" is not managed by the AWSLoadBalancerController "test" and is left untouched`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			controller := &albo.AWSLoadBalancerController{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec: albo.AWSLoadBalancerControllerSpec{
+					IngressClass:       tc.ingressClassName,
+					IngressClassParams: tc.ingressClassParams,
+				},
+				Status: albo.AWSLoadBalancerControllerStatus{IngressClass: tc.statusIngressClass},
+			}
+			testClient := fake.NewClientBuilder().WithScheme(test.Scheme).WithObjects(tc.existingObjects...).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &AWSLoadBalancerControllerReconciler{
+				Scheme:   test.Scheme,
+				Client:   testClient,
+				Recorder: recorder,
+			}
+			if err := r.ensureIngressClassParams(context.Background(), controller); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var paramsList elbv1beta1.IngressClassParamsList
+			if err := testClient.List(context.Background(), &paramsList); err != nil {
+				t.Fatalf("failed to list ingress class params: %v", err)
+			}
+			var actualParams []string
+			for _, params := range paramsList.Items {
+				actualParams = append(actualParams, params.Name)
+				if !metav1.IsControlledBy(&params, controller) {
+					for _, obj := range tc.existingObjects {
+						if existing, ok := obj.(*elbv1beta1.IngressClassParams); ok && existing.Name == params.Name {
+							if diff := cmp.Diff(existing.Spec, params.Spec); diff != "" {
+								t.Errorf("unexpected change of ingress class params %q not created by the operator (-want +got):\n%s", params.Name, diff)
+							}
+						}
+					}
+					continue
+				}
+				if params.Name == tc.ingressClassName && tc.ingressClassParams != nil {
+					expected := desiredIngressClassParams(tc.ingressClassName, tc.ingressClassParams)
+					if diff := cmp.Diff(expected.Spec, params.Spec); diff != "" {
+						t.Errorf("unexpected spec of ingress class params %q (-want +got):\n%s", params.Name, diff)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.expectedParams, actualParams); diff != "" {
+				t.Errorf("unexpected ingress class params (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.expectedEvents, recordedEvents(recorder)); diff != "" {
+				t.Errorf("unexpected events (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func testOwnedIngressClass(name string, withParams, isDefault bool) *networkingv1.IngressClass {
+	ingressClass := desiredIngressClass(name, withParams, isDefault)
+	ingressClass.OwnerReferences = []metav1.OwnerReference{testControllerOwnerReference()}
+	return ingressClass
+}
+
+func testOwnedIngressClassParams(name string, params *albo.AWSLoadBalancerIngressClassParams) *elbv1beta1.IngressClassParams {
+	ingressClassParams := desiredIngressClassParams(name, params)
+	ingressClassParams.OwnerReferences = []metav1.OwnerReference{testControllerOwnerReference()}
+	return ingressClassParams
+}
+
+func testControllerOwnerReference() metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: albo.GroupVersion.String(),
+		Kind:       "AWSLoadBalancerController",
+		Name:       "test",
+		Controller: ptr.To[bool](true),
+	}
+}
+
+func TestConflictingIngressClassOwner(t *testing.T) {
+	older := metav1.NewTime(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	newer := metav1.NewTime(older.Add(time.Hour))
+	testController := func(name, ingressClass string, created metav1.Time) *albo.AWSLoadBalancerController {
+		return &albo.AWSLoadBalancerController{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: created},
+			Spec:       albo.AWSLoadBalancerControllerSpec{IngressClass: ingressClass},
+		}
+	}
+
+	for _, tc := range []struct {
+		name                string
+		controller          *albo.AWSLoadBalancerController
+		existingControllers []client.Object
+		expectedConflict    string
+	}{
+		{
+			name:       "single instance",
+			controller: testController("cluster", "alb", older),
+			existingControllers: []client.Object{
+				testController("cluster", "alb", older),
+			},
+		},
+		{
+			name:       "instances with different ingress classes",
+			controller: testController("internal", "alb-internal", newer),
+			existingControllers: []client.Object{
+				testController("cluster", "alb", older),
+				testController("internal", "alb-internal", newer),
+			},
+		},
+		{
+			name:       "older instance with same ingress class",
+			controller: testController("internal", "alb", newer),
+			existingControllers: []client.Object{
+				testController("cluster", "alb", older),
+				testController("internal", "alb", newer),
+			},
+			expectedConflict: "cluster",
+		},
+		{
+			name:       "newer instance with same ingress class",
+			controller: testController("cluster", "alb", older),
+			existingControllers: []client.Object{
+				testController("cluster", "alb", older),
+				testController("internal", "alb", newer),
+			},
+		},
+		{
+			name:       "instances created at the same time",
+			controller: testController("internal", "alb", older),
+			existingControllers: []client.Object{
+				testController("cluster", "alb", older),
+				testController("internal", "alb", older),
+			},
+			expectedConflict: "cluster",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &AWSLoadBalancerControllerReconciler{
+				Client: fake.NewClientBuilder().WithObjects(tc.existingControllers...).WithScheme(test.Scheme).Build(),
+				Scheme: test.Scheme,
+			}
+			conflict, err := r.conflictingIngressClassOwner(context.Background(), tc.controller)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if conflict != tc.expectedConflict {
+				t.Errorf("unexpected conflicting instance, expected %q, got %q", tc.expectedConflict, conflict)
+			}
 		})
 	}
 }
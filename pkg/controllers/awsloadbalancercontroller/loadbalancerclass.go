@@ -0,0 +1,29 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+)
+
+const (
+	// defaultLoadBalancerClass is the load balancer class served by the controller when none is specified.
+	defaultLoadBalancerClass = "service.k8s.aws/nlb"
+)
+
+// loadBalancerClass returns the load balancer class of the Services served by the given instance.
+func loadBalancerClass(controller *albo.AWSLoadBalancerController) string {
+	if controller.Spec.LoadBalancerClass == "" {
+		return defaultLoadBalancerClass
+	}
+	return controller.Spec.LoadBalancerClass
+}
+
+// conflictingLoadBalancerClassOwner returns the name of the instance which claimed the load balancer class of the given instance first.
+// The instances which don't set the load balancer class use the default one.
+// An empty string is returned if no other instance uses the same load balancer class.
+func (r *AWSLoadBalancerControllerReconciler) conflictingLoadBalancerClassOwner(ctx context.Context, controller *albo.AWSLoadBalancerController) (string, error) {
+	return r.conflictingOwner(ctx, controller, func(other *albo.AWSLoadBalancerController) bool {
+		return loadBalancerClass(other) == loadBalancerClass(controller)
+	})
+}
@@ -0,0 +1,96 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+	"github.com/openshift/aws-load-balancer-operator/pkg/utils/test"
+)
+
+func TestConflictingLoadBalancerClassOwner(t *testing.T) {
+	older := metav1.NewTime(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	newer := metav1.NewTime(older.Add(time.Hour))
+	testController := func(name, ingressClass, loadBalancerClass string, created metav1.Time) *albo.AWSLoadBalancerController {
+		return &albo.AWSLoadBalancerController{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: created},
+			Spec:       albo.AWSLoadBalancerControllerSpec{IngressClass: ingressClass, LoadBalancerClass: loadBalancerClass},
+		}
+	}
+	for _, tc := range []struct {
+		name                string
+		controller          *albo.AWSLoadBalancerController
+		existingControllers []client.Object
+		expectedConflict    string
+	}{
+		{
+			name:       "single instance",
+			controller: testController("cluster", "alb", "", older),
+			existingControllers: []client.Object{
+				testController("cluster", "alb", "", older),
+			},
+		},
+		{
+			name:       "instances with different load balancer classes",
+			controller: testController("internal", "alb-internal", "service.k8s.aws/nlb-internal", newer),
+			existingControllers: []client.Object{
+				testController("cluster", "alb", "", older),
+				testController("internal", "alb-internal", "service.k8s.aws/nlb-internal", newer),
+			},
+		},
+		{
+			name:       "older instance with the default load balancer class",
+			controller: testController("internal", "alb-internal", "", newer),
+			existingControllers: []client.Object{
+				testController("cluster", "alb", "", older),
+				testController("internal", "alb-internal", "", newer),
+			},
+			expectedConflict: "cluster",
+		},
+		{
+			name:       "older instance with the default load balancer class set explicitly",
+			controller: testController("internal", "alb-internal", "", newer),
+			existingControllers: []client.Object{
+				testController("cluster", "alb", "service.k8s.aws/nlb", older),
+				testController("internal", "alb-internal", "", newer),
+			},
+			expectedConflict: "cluster",
+		},
+		{
+			name:       "newer instance with same load balancer class",
+			controller: testController("cluster", "alb", "example.com/nlb", older),
+			existingControllers: []client.Object{
+				testController("cluster", "alb", "example.com/nlb", older),
+				testController("internal", "alb-internal", "example.com/nlb", newer),
+			},
+		},
+		{
+			name:       "instances created at the same time",
+			controller: testController("internal", "alb-internal", "example.com/nlb", older),
+			existingControllers: []client.Object{
+				testController("cluster", "alb", "example.com/nlb", older),
+				testController("internal", "alb-internal", "example.com/nlb", older),
+			},
+			expectedConflict: "cluster",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &AWSLoadBalancerControllerReconciler{
+				Client: fake.NewClientBuilder().WithObjects(tc.existingControllers...).WithScheme(test.Scheme).Build(),
+				Scheme: test.Scheme,
+			}
+			conflict, err := r.conflictingLoadBalancerClassOwner(context.Background(), tc.controller)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if conflict != tc.expectedConflict {
+				t.Errorf("unexpected conflicting instance, expected %q, got %q", tc.expectedConflict, conflict)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package awsloadbalancercontroller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// controllerMetricLabel is the label holding the name of the AWSLoadBalancerController.
+	controllerMetricLabel = "controller"
+	// the roles of the subnets reported in the subnet metric
+	internalSubnetRole = "internal"
+	publicSubnetRole   = "public"
+	taggedSubnetRole   = "tagged"
+	untaggedSubnetRole = "untagged"
+	// conditionMetricName is the name of the metric reporting the status conditions, the alerts rely on it.
+	conditionMetricName = "aws_load_balancer_operator_status_condition"
+)
+
+var (
+	subnetsMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aws_load_balancer_operator_subnets",
+		Help: "Number of the subnets of the AWSLoadBalancerController by role. The tagged and untagged roles count the subnets with and without the tags added by the operator.",
+	}, []string{controllerMetricLabel, "role"})
+
+	credentialsSecretProvisionedMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "aws_load_balancer_operator_credentials_secret_provisioned",
+		Help: "Whether the credentials secret of the AWSLoadBalancerController is provisioned (1) or not (0).",
+	}, []string{controllerMetricLabel})
+
+	conditionMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: conditionMetricName,
+		Help: "Status of the conditions of the AWSLoadBalancerController: 1 if the condition is true, 0 otherwise.",
+	}, []string{controllerMetricLabel, "condition"})
+
+	lastSuccessfulReconcile = newLastReconcileCollector(time.Now)
+)
+
+func init() {
+	metrics.Registry.MustRegister(subnetsMetric, credentialsSecretProvisionedMetric, conditionMetric, lastSuccessfulReconcile)
+}
+
+// lastReconcileCollector exposes the time elapsed since the last successful reconciliation of each AWSLoadBalancerController.
+// The elapsed time is computed at the scrape time so that an instance which is not reconciled successfully anymore
+// is reported as such even though its reconciliation keeps being retried.
+type lastReconcileCollector struct {
+	lock      sync.Mutex
+	now       func() time.Time
+	last      map[string]time.Time
+	timestamp *prometheus.Desc
+	since     *prometheus.Desc
+}
+
+func newLastReconcileCollector(now func() time.Time) *lastReconcileCollector {
+	return &lastReconcileCollector{
+		now:  now,
+		last: make(map[string]time.Time),
+		timestamp: prometheus.NewDesc(
+			"aws_load_balancer_operator_last_successful_reconcile_timestamp_seconds",
+			"Unix timestamp of the last successful reconciliation of the AWSLoadBalancerController.",
+			[]string{controllerMetricLabel}, nil,
+		),
+		since: prometheus.NewDesc(
+			"aws_load_balancer_operator_seconds_since_last_successful_reconcile",
+			"Seconds elapsed since the last successful reconciliation of the AWSLoadBalancerController.",
+			[]string{controllerMetricLabel}, nil,
+		),
+	}
+}
+
+// observe records the successful reconciliation of the given instance.
+func (c *lastReconcileCollector) observe(name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.last[name] = c.now()
+}
+
+// forget stops reporting the given instance.
+func (c *lastReconcileCollector) forget(name string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.last, name)
+}
+
+// Describe implements prometheus.Collector.
+func (c *lastReconcileCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.timestamp
+	ch <- c.since
+}
+
+// Collect implements prometheus.Collector.
+func (c *lastReconcileCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := c.now()
+	for name, last := range c.last {
+		ch <- prometheus.MustNewConstMetric(c.timestamp, prometheus.GaugeValue, float64(last.Unix()), name)
+		ch <- prometheus.MustNewConstMetric(c.since, prometheus.GaugeValue, now.Sub(last).Seconds(), name)
+	}
+}
+
+// reportSubnets updates the subnet counts of the given instance.
+func reportSubnets(name string, internal, public, tagged, untagged []string) {
+	subnetsMetric.WithLabelValues(name, internalSubnetRole).Set(float64(len(internal)))
+	subnetsMetric.WithLabelValues(name, publicSubnetRole).Set(float64(len(public)))
+	subnetsMetric.WithLabelValues(name, taggedSubnetRole).Set(float64(len(tagged)))
+	subnetsMetric.WithLabelValues(name, untaggedSubnetRole).Set(float64(len(untagged)))
+}
+
+// reportCredentialsSecret updates the provisioning state of the credentials secret of the given instance.
+func reportCredentialsSecret(name string, provisioned bool) {
+	value := 0.0
+	if provisioned {
+		value = 1
+	}
+	credentialsSecretProvisionedMetric.WithLabelValues(name).Set(value)
+}
+
+// reportConditions updates the condition statuses of the given instance.
+func reportConditions(name string, conditions []metav1.Condition) {
+	for _, cond := range conditions {
+		value := 0.0
+		if cond.Status == metav1.ConditionTrue {
+			value = 1
+		}
+		conditionMetric.WithLabelValues(name, cond.Type).Set(value)
+	}
+}
+
+// forgetMetrics removes the metrics of the given instance.
+func forgetMetrics(name string) {
+	subnetsMetric.DeletePartialMatch(prometheus.Labels{controllerMetricLabel: name})
+	credentialsSecretProvisionedMetric.DeletePartialMatch(prometheus.Labels{controllerMetricLabel: name})
+	conditionMetric.DeletePartialMatch(prometheus.Labels{controllerMetricLabel: name})
+	lastSuccessfulReconcile.forget(name)
+}
@@ -0,0 +1,86 @@
+package awsloadbalancercontroller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLastReconcileCollector(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	collector := newLastReconcileCollector(func() time.Time { return now })
+
+	collector.observe("cluster")
+	collector.observe("internal")
+	collector.forget("internal")
+	now = now.Add(90 * time.Second)
+
+	expected := map[string]float64{
+		`aws_load_balancer_operator_last_successful_reconcile_timestamp_seconds{controller="cluster"}`: 1700000000,
+		`aws_load_balancer_operator_seconds_since_last_successful_reconcile{controller="cluster"}`:     90,
+	}
+	if diff := cmp.Diff(expected, gatherGauges(t, collector)); diff != "" {
+		t.Errorf("unexpected metrics (-want +got):\n%s", diff)
+	}
+}
+
+func TestReportMetrics(t *testing.T) {
+	reportSubnets("test-report", []string{"subnet-1"}, []string{"subnet-2", "subnet-3"}, []string{"subnet-2"}, nil)
+	reportCredentialsSecret("test-report", true)
+	reportConditions("test-report", []metav1.Condition{
+		{Type: DeploymentAvailableCondition, Status: metav1.ConditionTrue},
+		{Type: SubnetsAvailableCondition, Status: metav1.ConditionFalse},
+	})
+
+	expected := map[string]float64{
+		`aws_load_balancer_operator_subnets{controller="test-report",role="internal"}`:                          1,
+		`aws_load_balancer_operator_subnets{controller="test-report",role="public"}`:                            2,
+		`aws_load_balancer_operator_subnets{controller="test-report",role="tagged"}`:                            1,
+		`aws_load_balancer_operator_subnets{controller="test-report",role="untagged"}`:                          0,
+		`aws_load_balancer_operator_credentials_secret_provisioned{controller="test-report"}`:                   1,
+		`aws_load_balancer_operator_status_condition{condition="DeploymentAvailable",controller="test-report"}`: 1,
+		`aws_load_balancer_operator_status_condition{condition="SubnetsAvailable",controller="test-report"}`:    0,
+	}
+	if diff := cmp.Diff(expected, gatherGauges(t, subnetsMetric, credentialsSecretProvisionedMetric, conditionMetric)); diff != "" {
+		t.Errorf("unexpected metrics (-want +got):\n%s", diff)
+	}
+
+	forgetMetrics("test-report")
+	if metrics := gatherGauges(t, subnetsMetric, credentialsSecretProvisionedMetric, conditionMetric); len(metrics) != 0 {
+		t.Errorf("expected the metrics to be removed, got %v", metrics)
+	}
+}
+
+// gatherGauges returns the values of the gauges exposed by the given collectors
+// indexed by the metric name and labels.
+func gatherGauges(t *testing.T, collectors ...prometheus.Collector) map[string]float64 {
+	t.Helper()
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(collectors...)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	gauges := map[string]float64{}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			gauges[family.GetName()+formatLabels(m.GetLabel())] = m.GetGauge().GetValue()
+		}
+	}
+	return gauges
+}
+
+func formatLabels(labels []*dto.LabelPair) string {
+	formatted := "{"
+	for i, l := range labels {
+		if i > 0 {
+			formatted += ","
+		}
+		formatted += l.GetName() + `="` + l.GetValue() + `"`
+	}
+	return formatted + "}"
+}
@@ -0,0 +1,206 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/operator-framework/operator-lib/proxy"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+)
+
+const (
+	// namespaceNameLabelKey is the label set by Kubernetes on every namespace with the name of the namespace.
+	namespaceNameLabelKey = "kubernetes.io/metadata.name"
+	// monitoringNamespace is the namespace of the cluster monitoring Prometheus which scrapes the controller metrics.
+	monitoringNamespace = "openshift-monitoring"
+	// dnsNamespace is the namespace of the cluster DNS pods.
+	dnsNamespace = "openshift-dns"
+	// the ports of the cluster DNS: the service port and the port of the DNS pods
+	dnsPort    = 53
+	dnsPodPort = 5353
+	// apiServerPort is the port on which the API server is reached by the controller.
+	apiServerPort = 6443
+	// httpsPort is the port of the AWS API endpoints.
+	httpsPort = 443
+	// httpPort is the default port of the proxies with the http scheme.
+	httpPort = 80
+)
+
+// ensureNetworkPolicies ensures that the NetworkPolicies which restrict the traffic of the controller pods exist and are up-to-date.
+// The ingress policy only allows the webhook requests and the metrics scraping, the egress policy only allows
+// the traffic to the cluster DNS, the API server and the AWS API endpoints or the cluster wide proxy.
+func (r *AWSLoadBalancerControllerReconciler) ensureNetworkPolicies(ctx context.Context, namespace string, controller *albo.AWSLoadBalancerController, deployment *appsv1.Deployment) error {
+	prefix := fmt.Sprintf("%s-%s", controllerResourcePrefix, controller.Name)
+	desiredPolicies := []*networkingv1.NetworkPolicy{
+		desiredIngressNetworkPolicy(prefix+"-ingress", namespace, deployment.Spec.Selector),
+		desiredEgressNetworkPolicy(prefix+"-egress", namespace, deployment.Spec.Selector, proxy.ReadProxyVarsFromEnv()),
+	}
+
+	for _, desired := range desiredPolicies {
+		name := types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}
+		reqLogger := log.FromContext(ctx).WithValues("networkpolicy", name)
+		reqLogger.Info("ensuring network policy for aws-load-balancer-controller instance")
+
+		if err := controllerutil.SetControllerReference(controller, desired, r.Scheme); err != nil {
+			return fmt.Errorf("failed to set owner reference on desired network policy %q: %w", name, err)
+		}
+
+		var current networkingv1.NetworkPolicy
+		err := r.Get(ctx, name, &current)
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get existing network policy %q: %w", name, err)
+		}
+		if err != nil {
+			if err := r.Create(ctx, desired); err != nil {
+				return fmt.Errorf("failed to create network policy %q: %w", name, err)
+			}
+			continue
+		}
+
+		if equality.Semantic.DeepEqual(current.Spec, desired.Spec) {
+			continue
+		}
+		updated := current.DeepCopy()
+		updated.Spec = desired.Spec
+		if err := r.Update(ctx, updated); err != nil {
+			return fmt.Errorf("failed to update network policy %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// desiredIngressNetworkPolicy returns the NetworkPolicy which allows the webhook requests
+// and the scraping of the metrics by the cluster monitoring.
+func desiredIngressNetworkPolicy(name, namespace string, selector *metav1.LabelSelector) *networkingv1.NetworkPolicy {
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: *selector.DeepCopy(),
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					// the API server runs on the host network which cannot be selected by a peer,
+					// the webhook port is restricted instead of the source
+					Ports: []networkingv1.NetworkPolicyPort{tcpPort(controllerWebhookPort)},
+				},
+				{
+					From:  []networkingv1.NetworkPolicyPeer{namespacePeer(monitoringNamespace)},
+					Ports: []networkingv1.NetworkPolicyPort{tcpPort(controllerMetricsPort)},
+				},
+			},
+		},
+	}
+}
+
+// desiredEgressNetworkPolicy returns the NetworkPolicy which allows the traffic to the cluster DNS,
+// the API server and the AWS API endpoints. The ports of the proxies from the given proxy variables are allowed too.
+func desiredEgressNetworkPolicy(name, namespace string, selector *metav1.LabelSelector, proxyVars []corev1.EnvVar) *networkingv1.NetworkPolicy {
+	// the API server, the AWS API endpoints and the proxies are outside of the cluster network,
+	// only their ports are restricted: the API server runs on the host network of the control plane nodes
+	// which cannot be matched by a pod or namespace selector and whose addresses change when the nodes are replaced,
+	// the AWS API endpoints and the proxies resolve to addresses which are not known in advance and change over time
+	// so they cannot be listed in an IP block either
+	ports := []networkingv1.NetworkPolicyPort{tcpPort(apiServerPort), tcpPort(httpsPort)}
+	for _, port := range proxyPorts(proxyVars) {
+		if port != apiServerPort && port != httpsPort {
+			ports = append(ports, tcpPort(port))
+		}
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: *selector.DeepCopy(),
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress: []networkingv1.NetworkPolicyEgressRule{
+				{
+					To: []networkingv1.NetworkPolicyPeer{namespacePeer(dnsNamespace)},
+					Ports: []networkingv1.NetworkPolicyPort{
+						tcpPort(dnsPort),
+						udpPort(dnsPort),
+						tcpPort(dnsPodPort),
+						udpPort(dnsPodPort),
+					},
+				},
+				{
+					Ports: ports,
+				},
+			},
+		},
+	}
+}
+
+// proxyPorts returns the sorted ports of the proxies from the given proxy variables.
+// The default port of the proxy scheme is used if the proxy URL has no port.
+func proxyPorts(proxyVars []corev1.EnvVar) []int32 {
+	unique := map[int32]struct{}{}
+	for _, envVar := range proxyVars {
+		if envVar.Name != "HTTP_PROXY" && envVar.Name != "HTTPS_PROXY" || envVar.Value == "" {
+			continue
+		}
+		proxyURL, err := url.Parse(envVar.Value)
+		if err != nil {
+			continue
+		}
+		port := int32(httpPort)
+		if proxyURL.Scheme == "https" {
+			port = httpsPort
+		}
+		if proxyURL.Port() != "" {
+			parsed, err := strconv.ParseUint(proxyURL.Port(), 10, 16)
+			if err != nil {
+				continue
+			}
+			port = int32(parsed)
+		}
+		unique[port] = struct{}{}
+	}
+
+	ports := make([]int32, 0, len(unique))
+	for port := range unique {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return ports
+}
+
+func tcpPort(port int32) networkingv1.NetworkPolicyPort {
+	protocol := corev1.ProtocolTCP
+	p := intstr.FromInt32(port)
+	return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &p}
+}
+
+func udpPort(port int32) networkingv1.NetworkPolicyPort {
+	protocol := corev1.ProtocolUDP
+	p := intstr.FromInt32(port)
+	return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &p}
+}
+
+func namespacePeer(namespace string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{namespaceNameLabelKey: namespace},
+		},
+	}
+}
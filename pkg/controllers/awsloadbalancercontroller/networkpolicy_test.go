@@ -0,0 +1,134 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+	"github.com/openshift/aws-load-balancer-operator/pkg/utils/test"
+)
+
+func TestEnsureNetworkPolicies(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "controller"}}
+	outdatedIngressPolicy := desiredIngressNetworkPolicy("aws-load-balancer-controller-test-ingress", "test-namespace", selector)
+	outdatedIngressPolicy.Spec.Ingress = nil
+	outdatedIngressPolicy.Spec.PodSelector = metav1.LabelSelector{MatchLabels: map[string]string{"app": "other"}}
+
+	for _, tc := range []struct {
+		name            string
+		existingObjects []client.Object
+	}{
+		{
+			name: "new network policies",
+		},
+		{
+			name:            "existing network policy, rules modified",
+			existingObjects: []client.Object{outdatedIngressPolicy},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var existingObjects []client.Object
+			for _, obj := range tc.existingObjects {
+				existingObjects = append(existingObjects, obj.DeepCopyObject().(client.Object))
+			}
+			testClient := fake.NewClientBuilder().WithScheme(test.Scheme).WithObjects(existingObjects...).Build()
+			r := &AWSLoadBalancerControllerReconciler{
+				Client: testClient,
+				Scheme: test.Scheme,
+			}
+			controller := &albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+			deployment := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Selector: selector}}
+
+			err := r.ensureNetworkPolicies(context.Background(), "test-namespace", controller, deployment)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, expected := range []*networkingv1.NetworkPolicy{
+				desiredIngressNetworkPolicy("aws-load-balancer-controller-test-ingress", "test-namespace", selector),
+				desiredEgressNetworkPolicy("aws-load-balancer-controller-test-egress", "test-namespace", selector, nil),
+			} {
+				var policy networkingv1.NetworkPolicy
+				if err := testClient.Get(context.Background(), types.NamespacedName{Name: expected.Name, Namespace: expected.Namespace}, &policy); err != nil {
+					t.Fatalf("failed to get network policy %q: %v", expected.Name, err)
+				}
+				if diff := cmp.Diff(expected.Spec, policy.Spec); diff != "" {
+					t.Errorf("unexpected spec of network policy %q (-want +got):\n%s", expected.Name, diff)
+				}
+				if owners := policy.GetOwnerReferences(); len(tc.existingObjects) == 0 && (len(owners) != 1 || owners[0].Name != "test") {
+					t.Errorf("expected network policy %q to be owned by the controller, got %v", expected.Name, owners)
+				}
+			}
+		})
+	}
+}
+
+func TestProxyPorts(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		proxyVars     []corev1.EnvVar
+		expectedPorts []int32
+	}{
+		{
+			name:          "no proxy",
+			expectedPorts: []int32{},
+		},
+		{
+			name: "proxies with ports",
+			proxyVars: []corev1.EnvVar{
+				{Name: "HTTP_PROXY", Value: "http://proxy.example.com:3128"},
+				{Name: "http_proxy", Value: "http://proxy.example.com:3128"},
+				{Name: "HTTPS_PROXY", Value: "http://proxy.example.com:3130"},
+				{Name: "NO_PROXY", Value: "localhost:8080"},
+			},
+			expectedPorts: []int32{3128, 3130},
+		},
+		{
+			name: "default ports of the proxy schemes",
+			proxyVars: []corev1.EnvVar{
+				{Name: "HTTP_PROXY", Value: "http://proxy.example.com"},
+				{Name: "HTTPS_PROXY", Value: "https://proxy.example.com"},
+			},
+			expectedPorts: []int32{80, 443},
+		},
+		{
+			name: "invalid proxy",
+			proxyVars: []corev1.EnvVar{
+				{Name: "HTTPS_PROXY", Value: "http://proxy.example.com:port"},
+			},
+			expectedPorts: []int32{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expectedPorts, proxyPorts(tc.proxyVars)); diff != "" {
+				t.Errorf("unexpected proxy ports (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDesiredEgressNetworkPolicyProxy(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "controller"}}
+	policy := desiredEgressNetworkPolicy("test", "test-namespace", selector, []corev1.EnvVar{
+		{Name: "HTTPS_PROXY", Value: "http://proxy.example.com:3128"},
+		{Name: "HTTP_PROXY", Value: "https://proxy.example.com"},
+	})
+
+	var ports []int
+	for _, port := range policy.Spec.Egress[1].Ports {
+		ports = append(ports, port.Port.IntValue())
+	}
+	if diff := cmp.Diff([]int{apiServerPort, httpsPort, 3128}, ports); diff != "" {
+		t.Errorf("unexpected egress ports (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,116 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+)
+
+const (
+	// podDisruptionBudgetMinAvailable is the minimum number of the controller pods
+	// which have to stay available during voluntary disruptions like node drains.
+	// A single available replica is enough to serve the webhook requests.
+	podDisruptionBudgetMinAvailable = 1
+)
+
+// ensurePodDisruptionBudget ensures that the PodDisruptionBudget for the controller pods exists and is up-to-date
+// when the controller runs with multiple replicas. The PodDisruptionBudget is removed otherwise
+// as it would block the node drains for a single replica deployment.
+func (r *AWSLoadBalancerControllerReconciler) ensurePodDisruptionBudget(ctx context.Context, namespace string, controller *albo.AWSLoadBalancerController, deployment *appsv1.Deployment) error {
+	name := types.NamespacedName{
+		Name:      fmt.Sprintf("%s-%s", controllerResourcePrefix, controller.Name),
+		Namespace: namespace,
+	}
+
+	reqLogger := log.FromContext(ctx).WithValues("poddisruptionbudget", name)
+	reqLogger.Info("ensuring pod disruption budget for aws-load-balancer-controller instance")
+
+	current, exists, err := r.currentPodDisruptionBudget(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to get existing pod disruption budget %q: %w", name, err)
+	}
+
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas <= 1 {
+		if exists {
+			reqLogger.Info("deleting pod disruption budget as the controller has a single replica")
+			if err := r.Delete(ctx, current); err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete pod disruption budget %q: %w", name, err)
+			}
+		}
+		return nil
+	}
+
+	desired := desiredPodDisruptionBudget(name.Name, name.Namespace, deployment.Spec.Selector)
+	if err := controllerutil.SetControllerReference(controller, desired, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner reference on desired pod disruption budget %q: %w", name, err)
+	}
+
+	if !exists {
+		if err := r.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create pod disruption budget %q: %w", name, err)
+		}
+		return nil
+	}
+	return r.updatePodDisruptionBudget(ctx, current, desired)
+}
+
+func (r *AWSLoadBalancerControllerReconciler) currentPodDisruptionBudget(ctx context.Context, name types.NamespacedName) (*policyv1.PodDisruptionBudget, bool, error) {
+	var pdb policyv1.PodDisruptionBudget
+	err := r.Get(ctx, name, &pdb)
+	if err != nil && errors.IsNotFound(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return &pdb, true, nil
+}
+
+func desiredPodDisruptionBudget(name, namespace string, selector *metav1.LabelSelector) *policyv1.PodDisruptionBudget {
+	minAvailable := intstr.FromInt32(podDisruptionBudgetMinAvailable)
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable: &minAvailable,
+			Selector:     selector.DeepCopy(),
+		},
+	}
+}
+
+func (r *AWSLoadBalancerControllerReconciler) updatePodDisruptionBudget(ctx context.Context, current, desired *policyv1.PodDisruptionBudget) error {
+	updated := current.DeepCopy()
+	var outdated bool
+
+	if !equality.Semantic.DeepEqual(updated.Spec.MinAvailable, desired.Spec.MinAvailable) {
+		updated.Spec.MinAvailable = desired.Spec.MinAvailable
+		outdated = true
+	}
+	if updated.Spec.MaxUnavailable != nil {
+		updated.Spec.MaxUnavailable = nil
+		outdated = true
+	}
+	if !equality.Semantic.DeepEqual(updated.Spec.Selector, desired.Spec.Selector) {
+		updated.Spec.Selector = desired.Spec.Selector
+		outdated = true
+	}
+
+	if outdated {
+		return r.Update(ctx, updated)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+	"github.com/openshift/aws-load-balancer-operator/pkg/utils/test"
+)
+
+func testPodDisruptionBudget(name, namespace string, minAvailable, maxUnavailable *intstr.IntOrString, selector map[string]string) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable:   minAvailable,
+			MaxUnavailable: maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selector,
+			},
+		},
+	}
+}
+
+func TestEnsurePodDisruptionBudget(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		existingObjects []client.Object
+		replicas        *int32
+		expectedPDB     *policyv1.PodDisruptionBudget
+	}{
+		{
+			name:     "single replica, no pdb",
+			replicas: ptr.To[int32](1),
+		},
+		{
+			name: "replicas not set, no pdb",
+		},
+		{
+			name:     "multiple replicas, new pdb",
+			replicas: ptr.To[int32](2),
+			expectedPDB: testPodDisruptionBudget(
+				"aws-load-balancer-controller-test",
+				"test-namespace",
+				ptr.To(intstr.FromInt32(1)),
+				nil,
+				map[string]string{"app": "controller"},
+			),
+		},
+		{
+			name:     "multiple replicas, existing pdb modified",
+			replicas: ptr.To[int32](3),
+			existingObjects: []client.Object{
+				testPodDisruptionBudget(
+					"aws-load-balancer-controller-test",
+					"test-namespace",
+					nil,
+					ptr.To(intstr.FromString("50%")),
+					map[string]string{"app": "controller-old"},
+				),
+			},
+			expectedPDB: testPodDisruptionBudget(
+				"aws-load-balancer-controller-test",
+				"test-namespace",
+				ptr.To(intstr.FromInt32(1)),
+				nil,
+				map[string]string{"app": "controller"},
+			),
+		},
+		{
+			name:     "scaled down to single replica, existing pdb removed",
+			replicas: ptr.To[int32](1),
+			existingObjects: []client.Object{
+				testPodDisruptionBudget(
+					"aws-load-balancer-controller-test",
+					"test-namespace",
+					ptr.To(intstr.FromInt32(1)),
+					nil,
+					map[string]string{"app": "controller"},
+				),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			testClient := fake.NewClientBuilder().WithObjects(tc.existingObjects...).WithScheme(test.Scheme).Build()
+			r := &AWSLoadBalancerControllerReconciler{
+				Client: testClient,
+				Scheme: test.Scheme,
+			}
+			controller := &albo.AWSLoadBalancerController{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			}
+			deployment := &appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{
+					Replicas: tc.replicas,
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "controller"},
+					},
+				},
+			}
+			err := r.ensurePodDisruptionBudget(context.Background(), "test-namespace", controller, deployment)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var pdb policyv1.PodDisruptionBudget
+			err = testClient.Get(context.Background(), types.NamespacedName{Name: "aws-load-balancer-controller-test", Namespace: "test-namespace"}, &pdb)
+			if tc.expectedPDB == nil {
+				if err == nil {
+					t.Fatalf("expected pod disruption budget to be absent")
+				}
+				if !errors.IsNotFound(err) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get pod disruption budget: %v", err)
+			}
+			if !equality.Semantic.DeepEqual(pdb.Spec, tc.expectedPDB.Spec) {
+				t.Errorf("pod disruption budget has unexpected configuration:\n%s", cmp.Diff(pdb.Spec, tc.expectedPDB.Spec))
+			}
+		})
+	}
+}
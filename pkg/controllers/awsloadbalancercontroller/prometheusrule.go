@@ -0,0 +1,175 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+)
+
+const (
+	// the default thresholds of the alerts, they match the defaults of the API
+	defaultDeploymentUnavailableMinutes           = 15
+	defaultCredentialsSecretNotProvisionedMinutes = 15
+	defaultSubnetsSyncFailingMinutes              = 30
+	defaultAWSAPIErrorRatePercent                 = 10
+	// awsAPIErrorRateWindow is the window over which the error rate of the AWS API calls is computed.
+	awsAPIErrorRateWindow = "15m"
+	// controllerAPICallsMetricName is the metric of the controller counting its AWS API calls by error code.
+	controllerAPICallsMetricName = "aws_api_calls_total"
+)
+
+// prometheusRuleGVK is the kind of the PrometheusRule from the Prometheus operator.
+var prometheusRuleGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+
+// ensurePrometheusRule ensures that the PrometheusRule with the alerts of the controller exists and is up-to-date.
+// Nothing is done if the PrometheusRule CRD is not installed on the cluster.
+func (r *AWSLoadBalancerControllerReconciler) ensurePrometheusRule(ctx context.Context, namespace string, controller *albo.AWSLoadBalancerController, service string) error {
+	reqLogger := log.FromContext(ctx).WithValues("prometheusrule", types.NamespacedName{Name: service, Namespace: namespace})
+
+	available, err := r.kindInstalled(prometheusRuleGVK)
+	if err != nil {
+		return fmt.Errorf("failed to verify that the PrometheusRule CRD is installed: %w", err)
+	}
+	if !available {
+		reqLogger.V(1).Info("PrometheusRule CRD is not installed, skipping the PrometheusRule")
+		return nil
+	}
+	reqLogger.Info("ensuring prometheus rule for aws-load-balancer-controller instance")
+
+	desired := desiredPrometheusRule(service, namespace, controller)
+	if err := controllerutil.SetControllerReference(controller, desired, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner reference on desired prometheus rule %q: %w", service, err)
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(prometheusRuleGVK)
+	err = r.Get(ctx, types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, current)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get existing prometheus rule %q: %w", desired.GetName(), err)
+		}
+		if err := r.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create prometheus rule %q: %w", desired.GetName(), err)
+		}
+		return nil
+	}
+
+	if equality.Semantic.DeepEqual(current.Object["spec"], desired.Object["spec"]) {
+		return nil
+	}
+	updated := current.DeepCopy()
+	updated.Object["spec"] = desired.Object["spec"]
+	if err := r.Update(ctx, updated); err != nil {
+		return fmt.Errorf("failed to update prometheus rule %q: %w", desired.GetName(), err)
+	}
+	return nil
+}
+
+// desiredPrometheusRule returns the PrometheusRule with the alerts of the given controller.
+// The alerts on the operator side are driven by the status conditions of the controller,
+// the AWS API error rate alert uses the metrics of the controller scraped through the given service.
+func desiredPrometheusRule(service, namespace string, controller *albo.AWSLoadBalancerController) *unstructured.Unstructured {
+	deploymentUnavailable := int32(defaultDeploymentUnavailableMinutes)
+	credentialsNotProvisioned := int32(defaultCredentialsSecretNotProvisionedMinutes)
+	subnetsSyncFailing := int32(defaultSubnetsSyncFailingMinutes)
+	apiErrorRate := int32(defaultAWSAPIErrorRatePercent)
+	if alerts := controller.Spec.Alerts; alerts != nil {
+		if alerts.DeploymentUnavailableMinutes > 0 {
+			deploymentUnavailable = alerts.DeploymentUnavailableMinutes
+		}
+		if alerts.CredentialsSecretNotProvisionedMinutes > 0 {
+			credentialsNotProvisioned = alerts.CredentialsSecretNotProvisionedMinutes
+		}
+		if alerts.SubnetsSyncFailingMinutes > 0 {
+			subnetsSyncFailing = alerts.SubnetsSyncFailingMinutes
+		}
+		if alerts.AWSAPIErrorRatePercent > 0 {
+			apiErrorRate = alerts.AWSAPIErrorRatePercent
+		}
+	}
+
+	// the condition metrics are exposed by the operator which may run in another namespace than the controller,
+	// the instances are cluster scoped so the controller label alone selects the metrics of the instance
+	conditionExpr := func(condition string) string {
+		return fmt.Sprintf(`%s{%s=%q,condition=%q} == 0`, conditionMetricName, controllerMetricLabel, controller.Name, condition)
+	}
+	apiCallsSelector := fmt.Sprintf(`namespace=%q,job=%q`, namespace, service)
+
+	rules := []interface{}{
+		alertingRule(
+			"AWSLoadBalancerControllerDeploymentUnavailable",
+			conditionExpr(DeploymentAvailableCondition),
+			deploymentUnavailable,
+			"The AWS Load Balancer Controller is unavailable.",
+			fmt.Sprintf("Not all the replicas of the AWSLoadBalancerController %q have been available for %d minutes. Ingresses and services are not reconciled while no replica is available.", controller.Name, deploymentUnavailable),
+		),
+		alertingRule(
+			"AWSLoadBalancerControllerCredentialsSecretNotProvisioned",
+			conditionExpr(CredentialsSecretAvailableCondition),
+			credentialsNotProvisioned,
+			"The credentials of the AWS Load Balancer Controller are not provisioned.",
+			fmt.Sprintf("The credentials secret of the AWSLoadBalancerController %q has not been provisioned for %d minutes. The controller is not deployed until the secret is provisioned.", controller.Name, credentialsNotProvisioned),
+		),
+		alertingRule(
+			"AWSLoadBalancerControllerSubnetsSyncFailing",
+			conditionExpr(SubnetsAvailableCondition),
+			subnetsSyncFailing,
+			"The subnets of the AWS Load Balancer Controller cannot be discovered or tagged.",
+			fmt.Sprintf("The operator has been failing to discover or tag the subnets of the AWSLoadBalancerController %q for %d minutes. Check the SubnetsAvailable condition of the instance.", controller.Name, subnetsSyncFailing),
+		),
+		alertingRule(
+			"AWSLoadBalancerControllerAWSAPIErrorRateHigh",
+			fmt.Sprintf(`sum(rate(%s{%s,error_code!=""}[%s])) / sum(rate(%s{%s}[%s])) * 100 > %d`,
+				controllerAPICallsMetricName, apiCallsSelector, awsAPIErrorRateWindow, controllerAPICallsMetricName, apiCallsSelector, awsAPIErrorRateWindow, apiErrorRate),
+			0,
+			"The AWS Load Balancer Controller gets many errors from the AWS API.",
+			fmt.Sprintf("More than %d%% of the AWS API calls made by the AWSLoadBalancerController %q failed over the last %s.", apiErrorRate, controller.Name, awsAPIErrorRateWindow),
+		),
+	}
+
+	pr := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"groups": []interface{}{
+					map[string]interface{}{
+						"name":  "aws-load-balancer-controller",
+						"rules": rules,
+					},
+				},
+			},
+		},
+	}
+	pr.SetGroupVersionKind(prometheusRuleGVK)
+	pr.SetName(service)
+	pr.SetNamespace(namespace)
+	return pr
+}
+
+// alertingRule returns the alerting rule which fires after the expression is true for the given number of minutes.
+func alertingRule(name, expr string, forMinutes int32, summary, description string) map[string]interface{} {
+	rule := map[string]interface{}{
+		"alert": name,
+		"expr":  expr,
+		"labels": map[string]interface{}{
+			"severity": "warning",
+		},
+		"annotations": map[string]interface{}{
+			"summary":     summary,
+			"description": description,
+		},
+	}
+	if forMinutes > 0 {
+		rule["for"] = fmt.Sprintf("%dm", forMinutes)
+	}
+	return rule
+}
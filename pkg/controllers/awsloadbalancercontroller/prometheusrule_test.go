@@ -0,0 +1,209 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+	"github.com/openshift/aws-load-balancer-operator/pkg/utils/test"
+)
+
+func TestDesiredPrometheusRule(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		alerts        *albo.AWSLoadBalancerControllerAlerts
+		expectedRules map[string][2]string
+	}{
+		{
+			name: "default thresholds",
+			expectedRules: map[string][2]string{
+				"AWSLoadBalancerControllerDeploymentUnavailable": {
+					`aws_load_balancer_operator_status_condition{controller="test",condition="DeploymentAvailable"} == 0`,
+					"15m",
+				},
+				"AWSLoadBalancerControllerCredentialsSecretNotProvisioned": {
+					`aws_load_balancer_operator_status_condition{controller="test",condition="CredentialsSecretAvailable"} == 0`,
+					"15m",
+				},
+				"AWSLoadBalancerControllerSubnetsSyncFailing": {
+					`aws_load_balancer_operator_status_condition{controller="test",condition="SubnetsAvailable"} == 0`,
+					"30m",
+				},
+				"AWSLoadBalancerControllerAWSAPIErrorRateHigh": {
+					`sum(rate(aws_api_calls_total{namespace="test-namespace",job="aws-load-balancer-controller-test",error_code!=""}[15m])) / sum(rate(aws_api_calls_total{namespace="test-namespace",job="aws-load-balancer-controller-test"}[15m])) * 100 > 10`,
+					"",
+				},
+			},
+		},
+		{
+			name: "custom thresholds",
+			alerts: &albo.AWSLoadBalancerControllerAlerts{
+				DeploymentUnavailableMinutes:           5,
+				CredentialsSecretNotProvisionedMinutes: 60,
+				SubnetsSyncFailingMinutes:              10,
+				AWSAPIErrorRatePercent:                 50,
+			},
+			expectedRules: map[string][2]string{
+				"AWSLoadBalancerControllerDeploymentUnavailable": {
+					`aws_load_balancer_operator_status_condition{controller="test",condition="DeploymentAvailable"} == 0`,
+					"5m",
+				},
+				"AWSLoadBalancerControllerCredentialsSecretNotProvisioned": {
+					`aws_load_balancer_operator_status_condition{controller="test",condition="CredentialsSecretAvailable"} == 0`,
+					"60m",
+				},
+				"AWSLoadBalancerControllerSubnetsSyncFailing": {
+					`aws_load_balancer_operator_status_condition{controller="test",condition="SubnetsAvailable"} == 0`,
+					"10m",
+				},
+				"AWSLoadBalancerControllerAWSAPIErrorRateHigh": {
+					`sum(rate(aws_api_calls_total{namespace="test-namespace",job="aws-load-balancer-controller-test",error_code!=""}[15m])) / sum(rate(aws_api_calls_total{namespace="test-namespace",job="aws-load-balancer-controller-test"}[15m])) * 100 > 50`,
+					"",
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			controller := &albo.AWSLoadBalancerController{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				Spec:       albo.AWSLoadBalancerControllerSpec{Alerts: tc.alerts},
+			}
+			pr := desiredPrometheusRule("aws-load-balancer-controller-test", "test-namespace", controller)
+
+			groups, _, _ := unstructured.NestedSlice(pr.Object, "spec", "groups")
+			if len(groups) != 1 {
+				t.Fatalf("expected 1 rule group, got %d", len(groups))
+			}
+			rules, _, _ := unstructured.NestedSlice(groups[0].(map[string]interface{}), "rules")
+			actualRules := map[string][2]string{}
+			for _, rule := range rules {
+				rule := rule.(map[string]interface{})
+				forDuration, _ := rule["for"].(string)
+				actualRules[rule["alert"].(string)] = [2]string{rule["expr"].(string), forDuration}
+			}
+			if diff := cmp.Diff(tc.expectedRules, actualRules); diff != "" {
+				t.Errorf("unexpected rules (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEnsurePrometheusRule(t *testing.T) {
+	controller := &albo.AWSLoadBalancerController{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec: albo.AWSLoadBalancerControllerSpec{
+			Alerts: &albo.AWSLoadBalancerControllerAlerts{DeploymentUnavailableMinutes: 5},
+		},
+	}
+	outdatedRule := desiredPrometheusRule("aws-load-balancer-controller-test", "test-namespace", &albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "test"}})
+
+	for _, tc := range []struct {
+		name                    string
+		prometheusRuleInstalled bool
+		existingObjects         []client.Object
+		expectPrometheusRule    bool
+	}{
+		{
+			name: "PrometheusRule CRD not installed",
+		},
+		{
+			name:                    "new prometheus rule",
+			prometheusRuleInstalled: true,
+			expectPrometheusRule:    true,
+		},
+		{
+			name:                    "existing prometheus rule, thresholds modified",
+			prometheusRuleInstalled: true,
+			existingObjects:         []client.Object{outdatedRule},
+			expectPrometheusRule:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mapper := meta.NewDefaultRESTMapper(nil)
+			if tc.prometheusRuleInstalled {
+				mapper.Add(prometheusRuleGVK, meta.RESTScopeNamespace)
+			}
+			var existingObjects []client.Object
+			for _, obj := range tc.existingObjects {
+				existingObjects = append(existingObjects, obj.DeepCopyObject().(client.Object))
+			}
+			testClient := fake.NewClientBuilder().WithScheme(test.Scheme).WithRESTMapper(mapper).WithObjects(existingObjects...).Build()
+			r := &AWSLoadBalancerControllerReconciler{
+				Client: testClient,
+				Scheme: test.Scheme,
+			}
+
+			err := r.ensurePrometheusRule(context.Background(), "test-namespace", controller, "aws-load-balancer-controller-test")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tc.expectPrometheusRule {
+				return
+			}
+
+			pr := &unstructured.Unstructured{}
+			pr.SetGroupVersionKind(prometheusRuleGVK)
+			if err := testClient.Get(context.Background(), types.NamespacedName{Name: "aws-load-balancer-controller-test", Namespace: "test-namespace"}, pr); err != nil {
+				t.Fatalf("failed to get prometheus rule: %v", err)
+			}
+			expected := desiredPrometheusRule("aws-load-balancer-controller-test", "test-namespace", controller)
+			if diff := cmp.Diff(expected.Object["spec"], pr.Object["spec"]); diff != "" {
+				t.Errorf("unexpected prometheus rule spec (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// controllerMetrics are the metrics exposed by the aws-load-balancer-controller version deployed by the operator
+// (sigs.k8s.io/aws-load-balancer-controller v0.0.0-20240809195826-f39ae43121c3, see pkg/aws/metrics of the module).
+var controllerMetrics = sets.New[string](
+	"aws_api_calls_total",
+	"aws_api_call_duration_seconds",
+	"aws_api_call_retries",
+	"aws_api_requests_total",
+	"aws_api_request_duration_seconds",
+)
+
+func TestPrometheusRuleMetricsExposed(t *testing.T) {
+	// the vectors are only gathered once they have a sample
+	reportConditions("test-exposed", []metav1.Condition{{Type: DeploymentAvailableCondition, Status: metav1.ConditionFalse}})
+	defer forgetMetrics("test-exposed")
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather the operator metrics: %v", err)
+	}
+	exposed := controllerMetrics.Clone()
+	for _, family := range families {
+		exposed.Insert(family.GetName())
+	}
+
+	controller := &albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "test"}}
+	pr := desiredPrometheusRule("aws-load-balancer-controller-test", "test-namespace", controller)
+	groups, _, _ := unstructured.NestedSlice(pr.Object, "spec", "groups")
+	rules, _, _ := unstructured.NestedSlice(groups[0].(map[string]interface{}), "rules")
+	metricName := regexp.MustCompile(`([a-zA-Z_:][a-zA-Z0-9_:]*)\{`)
+	for _, rule := range rules {
+		rule := rule.(map[string]interface{})
+		expr := rule["expr"].(string)
+		matches := metricName.FindAllStringSubmatch(expr, -1)
+		if len(matches) == 0 {
+			t.Errorf("no metric found in the expression of alert %s: %s", rule["alert"], expr)
+		}
+		for _, match := range matches {
+			if !exposed.Has(match[1]) {
+				t.Errorf("alert %s uses metric %s which is exposed neither by the operator nor by the controller", rule["alert"], match[1])
+			}
+		}
+	}
+}
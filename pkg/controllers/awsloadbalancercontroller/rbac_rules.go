@@ -2,8 +2,9 @@ package awsloadbalancercontroller
 
 import rbacv1 "k8s.io/api/rbac/v1"
 
-// getLeaderElectionRules is a set of rules required for leader election by the controller.
-func getLeaderElectionRules() []rbacv1.PolicyRule {
+// getLeaderElectionRules is a set of rules required for leader election by the controller
+// which uses the given leader election ID.
+func getLeaderElectionRules(leaderElectionID string) []rbacv1.PolicyRule {
 	return []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{""},
@@ -13,7 +14,7 @@ func getLeaderElectionRules() []rbacv1.PolicyRule {
 		{
 			APIGroups:     []string{""},
 			Resources:     []string{"configmaps"},
-			ResourceNames: []string{"aws-load-balancer-controller-leader"},
+			ResourceNames: []string{leaderElectionID},
 			Verbs:         []string{"get", "update", "patch"},
 		},
 		{
@@ -24,8 +25,19 @@ func getLeaderElectionRules() []rbacv1.PolicyRule {
 		{
 			APIGroups:     []string{"coordination.k8s.io"},
 			Resources:     []string{"leases"},
-			ResourceNames: []string{"aws-load-balancer-controller-leader"},
+			ResourceNames: []string{leaderElectionID},
 			Verbs:         []string{"get", "update", "patch"},
 		},
 	}
 }
+
+// getPrometheusRules is a set of rules required by Prometheus to discover the scraped pods of a namespace.
+func getPrometheusRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{""},
+			Resources: []string{"services", "endpoints", "pods"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+	}
+}
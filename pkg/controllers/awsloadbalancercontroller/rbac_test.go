@@ -189,7 +189,7 @@ func testPreExistingClusterRole() *rbacv1.ClusterRole {
 }
 
 func testPreExistingRole() *rbacv1.Role {
-	return buildRole(testResourceName, test.OperatorNamespace, getLeaderElectionRules())
+	return buildRole(testResourceName, test.OperatorNamespace, getLeaderElectionRules(defaultLeaderElectionID))
 }
 
 func testOutDatedPreExistingRole() *rbacv1.Role {
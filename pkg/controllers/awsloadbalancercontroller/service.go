@@ -47,10 +47,16 @@ func (r *AWSLoadBalancerControllerReconciler) ensureService(ctx context.Context,
 }
 
 func desiredService(name, namespace string, servingSecretName string, selector map[string]string) *corev1.Service {
+	// the service is labeled like the pods it selects so that the service monitor can select it
+	labels := make(map[string]string, len(selector))
+	for k, v := range selector {
+		labels[k] = v
+	}
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
+			Labels:    labels,
 			Annotations: map[string]string{
 				servingSecretAnnotationName: servingSecretName,
 			},
@@ -102,6 +108,16 @@ func (r *AWSLoadBalancerControllerReconciler) updateService(ctx context.Context,
 		}
 	}
 
+	if updatedService.Labels == nil {
+		updatedService.Labels = make(map[string]string)
+	}
+	for labelKey, labelValue := range desired.Labels {
+		if currentLabelValue, ok := updatedService.Labels[labelKey]; !ok || currentLabelValue != labelValue {
+			updatedService.Labels[labelKey] = labelValue
+			updated = true
+		}
+	}
+
 	if updated {
 		return updatedService, r.Update(ctx, updatedService)
 	}
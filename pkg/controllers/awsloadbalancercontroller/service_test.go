@@ -24,6 +24,7 @@ func testControllerService(name, namespace string, selector, annotations map[str
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
 			Namespace:   namespace,
+			Labels:      selector,
 			Annotations: annotations,
 		},
 		Spec: corev1.ServiceSpec{
@@ -245,6 +246,10 @@ func TestEnsureService(t *testing.T) {
 				t.Errorf("unexpected annotations\n%s", diff)
 			}
 
+			if diff := cmp.Diff(tc.expectedService.Labels, s.Labels); diff != "" {
+				t.Errorf("unexpected labels\n%s", diff)
+			}
+
 			if !equality.Semantic.DeepEqual(s.Spec, tc.expectedService.Spec) {
 				t.Errorf("service has unexpected configuration:\n%s", cmp.Diff(s.Spec, tc.expectedService.Spec))
 			}
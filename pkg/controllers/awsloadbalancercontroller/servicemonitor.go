@@ -0,0 +1,196 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+)
+
+const (
+	// clusterMonitoringLabelKey is the label which enables the scraping of the namespace by the cluster monitoring stack.
+	clusterMonitoringLabelKey = "openshift.io/cluster-monitoring"
+	// prometheusServiceAccountName is the service account of the Prometheus of the cluster monitoring stack.
+	prometheusServiceAccountName = "prometheus-k8s"
+)
+
+// serviceMonitorGVK is the kind of the ServiceMonitor from the Prometheus operator.
+// The unstructured objects are used as the Prometheus operator API is not a dependency of the operator
+// and the ServiceMonitor CRD is not guaranteed to be installed on the cluster.
+var serviceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+
+// ensureServiceMonitor ensures that the ServiceMonitor which scrapes the metrics of the controller exists and is up-to-date.
+// Nothing is done if the ServiceMonitor CRD is not installed on the cluster.
+func (r *AWSLoadBalancerControllerReconciler) ensureServiceMonitor(ctx context.Context, namespace string, controller *albo.AWSLoadBalancerController, service *corev1.Service) error {
+	reqLogger := log.FromContext(ctx).WithValues("servicemonitor", types.NamespacedName{Name: service.Name, Namespace: namespace})
+
+	available, err := r.kindInstalled(serviceMonitorGVK)
+	if err != nil {
+		return fmt.Errorf("failed to verify that the ServiceMonitor CRD is installed: %w", err)
+	}
+	if !available {
+		reqLogger.V(1).Info("ServiceMonitor CRD is not installed, skipping the ServiceMonitor")
+		return nil
+	}
+	reqLogger.Info("ensuring service monitor for aws-load-balancer-controller instance")
+
+	if err := r.ensureClusterMonitoringLabel(ctx, namespace); err != nil {
+		return err
+	}
+
+	// Prometheus discovers the scraped pods through the services and the endpoints of the namespace
+	if err := r.ensurePrometheusRoleAndBinding(ctx, namespace, controller); err != nil {
+		return err
+	}
+
+	desired := desiredServiceMonitor(service)
+	if err := controllerutil.SetControllerReference(controller, desired, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner reference on desired service monitor %q: %w", service.Name, err)
+	}
+
+	return r.applyServiceMonitor(ctx, desired)
+}
+
+// ensurePrometheusRoleAndBinding ensures that the Role and the RoleBinding which let the Prometheus
+// of the cluster monitoring stack discover the controller pods in the given namespace exist and are up-to-date.
+func (r *AWSLoadBalancerControllerReconciler) ensurePrometheusRoleAndBinding(ctx context.Context, namespace string, controller *albo.AWSLoadBalancerController) error {
+	name := fmt.Sprintf("%s-%s-prometheus", controllerResourcePrefix, controller.Name)
+
+	role := buildRole(name, namespace, getPrometheusRules())
+	if err := controllerutil.SetControllerReference(controller, role, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set the controller reference for role %s: %w", name, err)
+	}
+	exist, current, err := r.currentRole(ctx, role)
+	if err != nil {
+		return fmt.Errorf("failed to fetch current role %s: %w", name, err)
+	}
+	if !exist {
+		if err := r.createRole(ctx, role); err != nil {
+			return err
+		}
+	} else if err := r.updateRole(ctx, current, role); err != nil {
+		return fmt.Errorf("failed to update role %s: %w", name, err)
+	}
+
+	prometheusSA := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: prometheusServiceAccountName, Namespace: monitoringNamespace}}
+	binding := buildRoleBinding(name, namespace, name, prometheusSA)
+	if err := controllerutil.SetControllerReference(controller, binding, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set the controller reference for rolebinding %s: %w", name, err)
+	}
+	exist, currentBinding, err := r.currentRoleBinding(ctx, binding)
+	if err != nil {
+		return fmt.Errorf("failed to fetch current rolebinding %s: %w", name, err)
+	}
+	if !exist {
+		return r.createRoleBinding(ctx, binding)
+	}
+	if err := r.updateRoleBinding(ctx, currentBinding, binding); err != nil {
+		return fmt.Errorf("failed to update rolebinding %s: %w", name, err)
+	}
+	return nil
+}
+
+// applyServiceMonitor creates the given ServiceMonitor or updates its spec if it already exists.
+func (r *AWSLoadBalancerControllerReconciler) applyServiceMonitor(ctx context.Context, desired *unstructured.Unstructured) error {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(serviceMonitorGVK)
+	err := r.Get(ctx, types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, current)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get existing service monitor %q: %w", desired.GetName(), err)
+		}
+		if err := r.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create service monitor %q: %w", desired.GetName(), err)
+		}
+		return nil
+	}
+
+	if equality.Semantic.DeepEqual(current.Object["spec"], desired.Object["spec"]) {
+		return nil
+	}
+	updated := current.DeepCopy()
+	updated.Object["spec"] = desired.Object["spec"]
+	if err := r.Update(ctx, updated); err != nil {
+		return fmt.Errorf("failed to update service monitor %q: %w", desired.GetName(), err)
+	}
+	return nil
+}
+
+// kindInstalled checks whether the CRD of the given kind is installed on the cluster.
+func (r *AWSLoadBalancerControllerReconciler) kindInstalled(gvk schema.GroupVersionKind) (bool, error) {
+	_, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ensureClusterMonitoringLabel labels the operator namespace so that the cluster monitoring stack
+// picks up the ServiceMonitors from it.
+func (r *AWSLoadBalancerControllerReconciler) ensureClusterMonitoringLabel(ctx context.Context, namespace string) error {
+	var ns corev1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		return fmt.Errorf("failed to get namespace %q: %w", namespace, err)
+	}
+	if ns.Labels[clusterMonitoringLabelKey] == "true" {
+		return nil
+	}
+	updated := ns.DeepCopy()
+	if updated.Labels == nil {
+		updated.Labels = map[string]string{}
+	}
+	updated.Labels[clusterMonitoringLabelKey] = "true"
+	if err := r.Update(ctx, updated); err != nil {
+		return fmt.Errorf("failed to add label %s to namespace %q: %w", clusterMonitoringLabelKey, namespace, err)
+	}
+	return nil
+}
+
+// desiredServiceMonitor returns the ServiceMonitor which scrapes the metrics port of the given service.
+// The metrics are scraped over plain HTTP: the controller serves them without TLS and has no option
+// to use the serving certificate for its metrics server.
+func desiredServiceMonitor(service *corev1.Service) *unstructured.Unstructured {
+	matchLabels := map[string]interface{}{}
+	for k, v := range service.Labels {
+		matchLabels[k] = v
+	}
+
+	sm := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"endpoints": []interface{}{
+					map[string]interface{}{
+						"port":   "metrics",
+						"path":   "/metrics",
+						"scheme": "http",
+					},
+				},
+				"namespaceSelector": map[string]interface{}{
+					"matchNames": []interface{}{service.Namespace},
+				},
+				"selector": map[string]interface{}{
+					"matchLabels": matchLabels,
+				},
+			},
+		},
+	}
+	sm.SetGroupVersionKind(serviceMonitorGVK)
+	sm.SetName(service.Name)
+	sm.SetNamespace(service.Namespace)
+	return sm
+}
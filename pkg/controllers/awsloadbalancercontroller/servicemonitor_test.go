@@ -0,0 +1,170 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+	"github.com/openshift/aws-load-balancer-operator/pkg/utils/test"
+)
+
+func TestEnsureServiceMonitor(t *testing.T) {
+	service := desiredService("aws-load-balancer-controller-test", "test-namespace", "serving-secret", map[string]string{"app": "controller"})
+	outdatedServiceMonitor := desiredServiceMonitor(service)
+	outdatedServiceMonitor.Object["spec"].(map[string]interface{})["endpoints"] = []interface{}{
+		map[string]interface{}{
+			"port":      "metrics",
+			"scheme":    "https",
+			"tlsConfig": map[string]interface{}{"serverName": "aws-load-balancer-controller-test.test-namespace.svc"},
+		},
+	}
+
+	controller := &albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "test", UID: "test-uid"}}
+	outdatedRole := buildRole("aws-load-balancer-controller-test-prometheus", "test-namespace", []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"services"}, Verbs: []string{"get"}},
+	})
+	_ = controllerutil.SetControllerReference(controller, outdatedRole, test.Scheme)
+
+	for _, tc := range []struct {
+		name                    string
+		serviceMonitorInstalled bool
+		existingObjects         []client.Object
+		expectServiceMonitor    bool
+		expectedNamespaceLabels map[string]string
+	}{
+		{
+			name: "ServiceMonitor CRD not installed",
+			existingObjects: []client.Object{
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}},
+			},
+		},
+		{
+			name:                    "new service monitor",
+			serviceMonitorInstalled: true,
+			existingObjects: []client.Object{
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", Labels: map[string]string{"extra": "label"}}},
+			},
+			expectServiceMonitor:    true,
+			expectedNamespaceLabels: map[string]string{"extra": "label", clusterMonitoringLabelKey: "true"},
+		},
+		{
+			name:                    "existing service monitor, spec modified",
+			serviceMonitorInstalled: true,
+			existingObjects: []client.Object{
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace", Labels: map[string]string{clusterMonitoringLabelKey: "true"}}},
+				outdatedServiceMonitor,
+				outdatedRole,
+			},
+			expectServiceMonitor:    true,
+			expectedNamespaceLabels: map[string]string{clusterMonitoringLabelKey: "true"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mapper := meta.NewDefaultRESTMapper(nil)
+			if tc.serviceMonitorInstalled {
+				mapper.Add(serviceMonitorGVK, meta.RESTScopeNamespace)
+			}
+			var existingObjects []client.Object
+			for _, obj := range tc.existingObjects {
+				existingObjects = append(existingObjects, obj.DeepCopyObject().(client.Object))
+			}
+			testClient := fake.NewClientBuilder().WithScheme(test.Scheme).WithRESTMapper(mapper).WithObjects(existingObjects...).Build()
+			r := &AWSLoadBalancerControllerReconciler{
+				Client: testClient,
+				Scheme: test.Scheme,
+			}
+
+			err := r.ensureServiceMonitor(context.Background(), "test-namespace", controller, service)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var ns corev1.Namespace
+			if err := testClient.Get(context.Background(), types.NamespacedName{Name: "test-namespace"}, &ns); err != nil {
+				t.Fatalf("failed to get namespace: %v", err)
+			}
+			prometheusRBAC := types.NamespacedName{Name: "aws-load-balancer-controller-test-prometheus", Namespace: "test-namespace"}
+			if !tc.serviceMonitorInstalled {
+				if _, ok := ns.Labels[clusterMonitoringLabelKey]; ok {
+					t.Errorf("unexpected label %s on the namespace", clusterMonitoringLabelKey)
+				}
+				if err := testClient.Get(context.Background(), prometheusRBAC, &rbacv1.Role{}); !errors.IsNotFound(err) {
+					t.Errorf("expected no role for Prometheus, got %v", err)
+				}
+				return
+			}
+
+			var role rbacv1.Role
+			if err := testClient.Get(context.Background(), prometheusRBAC, &role); err != nil {
+				t.Fatalf("failed to get role: %v", err)
+			}
+			expectedRules := []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"services", "endpoints", "pods"}, Verbs: []string{"get", "list", "watch"}},
+			}
+			if diff := cmp.Diff(expectedRules, role.Rules); diff != "" {
+				t.Errorf("unexpected role rules (-want +got):\n%s", diff)
+			}
+			var binding rbacv1.RoleBinding
+			if err := testClient.Get(context.Background(), prometheusRBAC, &binding); err != nil {
+				t.Fatalf("failed to get rolebinding: %v", err)
+			}
+			expectedSubjects := []rbacv1.Subject{{Kind: "ServiceAccount", Name: "prometheus-k8s", Namespace: "openshift-monitoring"}}
+			if diff := cmp.Diff(expectedSubjects, binding.Subjects); diff != "" {
+				t.Errorf("unexpected rolebinding subjects (-want +got):\n%s", diff)
+			}
+			if binding.RoleRef.Kind != "Role" || binding.RoleRef.Name != role.Name {
+				t.Errorf("expected rolebinding to refer to role %s, got %v", role.Name, binding.RoleRef)
+			}
+			if !metav1.IsControlledBy(&role, controller) || !metav1.IsControlledBy(&binding, controller) {
+				t.Errorf("expected role and rolebinding to be controlled by the AWSLoadBalancerController")
+			}
+			if diff := cmp.Diff(tc.expectedNamespaceLabels, ns.Labels); diff != "" {
+				t.Errorf("unexpected namespace labels (-want +got):\n%s", diff)
+			}
+
+			serviceMonitor := &unstructured.Unstructured{}
+			serviceMonitor.SetGroupVersionKind(serviceMonitorGVK)
+			if err := testClient.Get(context.Background(), types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, serviceMonitor); err != nil {
+				t.Fatalf("failed to get service monitor: %v", err)
+			}
+			if diff := cmp.Diff(desiredServiceMonitor(service).Object["spec"], serviceMonitor.Object["spec"]); diff != "" {
+				t.Errorf("unexpected service monitor spec (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDesiredServiceMonitor(t *testing.T) {
+	service := desiredService("aws-load-balancer-controller-test", "test-namespace", "serving-secret", map[string]string{"app": "controller"})
+	sm := desiredServiceMonitor(service)
+
+	endpoints, _, _ := unstructured.NestedSlice(sm.Object, "spec", "endpoints")
+	if len(endpoints) != 1 {
+		t.Fatalf("expected 1 endpoint, got %d", len(endpoints))
+	}
+	// the controller serves its metrics over plain HTTP on the metrics port
+	expectedEndpoint := map[string]interface{}{
+		"port":   "metrics",
+		"path":   "/metrics",
+		"scheme": "http",
+	}
+	if diff := cmp.Diff(expectedEndpoint, endpoints[0]); diff != "" {
+		t.Errorf("unexpected endpoint (-want +got):\n%s", diff)
+	}
+	matchLabels, _, _ := unstructured.NestedStringMap(sm.Object, "spec", "selector", "matchLabels")
+	if diff := cmp.Diff(service.Labels, matchLabels); diff != "" {
+		t.Errorf("unexpected selector (-want +got):\n%s", diff)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 
 	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+	"github.com/openshift/aws-load-balancer-operator/pkg/aws"
 	"github.com/openshift/aws-load-balancer-operator/pkg/utils"
 )
 
@@ -18,6 +19,11 @@ const (
 	DeploymentAvailableCondition        = "DeploymentAvailable"
 	DeploymentUpgradingCondition        = "DeploymentUpgrading"
 	CredentialsSecretAvailableCondition = "CredentialsSecretAvailable"
+	IngressClassAvailableCondition      = "IngressClassAvailable"
+	LoadBalancerClassAvailableCondition = "LoadBalancerClassAvailable"
+	DeletionBlockedCondition            = "DeletionBlocked"
+	SubnetsAvailableCondition           = "SubnetsAvailable"
+	ClusterInfoResolvedCondition        = "ClusterInfoResolved"
 )
 
 func (r *AWSLoadBalancerControllerReconciler) updateControllerStatus(ctx context.Context, controller *albo.AWSLoadBalancerController, deployment *appsv1.Deployment, secretName string, secretProvisioned bool) error {
@@ -29,6 +35,23 @@ func (r *AWSLoadBalancerControllerReconciler) updateControllerStatus(ctx context
 		status.Conditions = mergeConditions(status.Conditions, deploymentConditions(deployment, controller.Generation)...)
 	}
 
+	reportConditions(controller.Name, status.Conditions)
+
+	if haveConditionsChanged(controller.Status.Conditions, status.Conditions) {
+		controller.Status.Conditions = status.Conditions
+		return r.Status().Update(ctx, controller)
+	}
+	return nil
+}
+
+// updateStatusConditions merges the given conditions into the status of the controller
+// and updates the status if any of them changed.
+func (r *AWSLoadBalancerControllerReconciler) updateStatusConditions(ctx context.Context, controller *albo.AWSLoadBalancerController, conditions ...metav1.Condition) error {
+	status := controller.Status.DeepCopy()
+	status.Conditions = mergeConditions(status.Conditions, conditions...)
+
+	reportConditions(controller.Name, status.Conditions)
+
 	if haveConditionsChanged(controller.Status.Conditions, status.Conditions) {
 		controller.Status.Conditions = status.Conditions
 		return r.Status().Update(ctx, controller)
@@ -36,6 +59,119 @@ func (r *AWSLoadBalancerControllerReconciler) updateControllerStatus(ctx context
 	return nil
 }
 
+func subnetsConditions(syncErr error, generation int64) []metav1.Condition {
+	if syncErr != nil {
+		reason, message := "SubnetsSyncFailed", fmt.Sprintf("Failed to discover and tag the subnets: %v", syncErr)
+		if aws.IsTransient(syncErr) {
+			reason, message = transientAWSFailure(syncErr)
+		}
+		return []metav1.Condition{
+			{
+				Type:               SubnetsAvailableCondition,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: generation,
+				Reason:             reason,
+				Message:            message,
+			},
+		}
+	}
+	return []metav1.Condition{
+		{
+			Type:               SubnetsAvailableCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             "SubnetsSynced",
+			Message:            "Subnets discovered and tagged",
+		},
+	}
+}
+
+func clusterInfoConditions(resolveErr error, vpcSource aws.VPCSource, generation int64) []metav1.Condition {
+	if resolveErr != nil {
+		reason, message := "ClusterInfoResolutionFailed", fmt.Sprintf("Failed to resolve the cluster name, AWS region and VPC: %v", resolveErr)
+		if aws.IsTransient(resolveErr) {
+			reason, message = transientAWSFailure(resolveErr)
+		}
+		return []metav1.Condition{
+			{
+				Type:               ClusterInfoResolvedCondition,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: generation,
+				Reason:             reason,
+				Message:            message,
+			},
+		}
+	}
+	message := "Cluster name, AWS region and VPC resolved"
+	if vpcSource != "" {
+		message = fmt.Sprintf("%s, VPC discovered from %s", message, vpcSource)
+	}
+	return []metav1.Condition{
+		{
+			Type:               ClusterInfoResolvedCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             "ClusterInfoResolved",
+			Message:            message,
+		},
+	}
+}
+
+// transientAWSFailure returns the reason and the message of the condition set
+// when the AWS API requests failed temporarily and are retried with a backoff.
+func transientAWSFailure(err error) (reason, message string) {
+	if aws.IsThrottled(err) {
+		return "AWSAPIThrottled", "AWS API requests are throttled, retrying with backoff"
+	}
+	return "AWSAPIUnavailable", fmt.Sprintf("AWS API requests failed temporarily, retrying with backoff: %v", err)
+}
+
+func ingressClassConditions(ingressClass, conflictingController string, generation int64) []metav1.Condition {
+	if conflictingController != "" {
+		return []metav1.Condition{
+			{
+				Type:               IngressClassAvailableCondition,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: generation,
+				Reason:             "IngressClassConflict",
+				Message:            fmt.Sprintf("IngressClass %q is already used by AWSLoadBalancerController %q", ingressClass, conflictingController),
+			},
+		}
+	}
+	return []metav1.Condition{
+		{
+			Type:               IngressClassAvailableCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             "IngressClassNotInConflict",
+			Message:            fmt.Sprintf("IngressClass %q is not used by any other AWSLoadBalancerController", ingressClass),
+		},
+	}
+}
+
+func loadBalancerClassConditions(loadBalancerClass, conflictingController string, generation int64) []metav1.Condition {
+	if conflictingController != "" {
+		return []metav1.Condition{
+			{
+				Type:               LoadBalancerClassAvailableCondition,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: generation,
+				Reason:             "LoadBalancerClassConflict",
+				Message:            fmt.Sprintf("LoadBalancerClass %q is already used by AWSLoadBalancerController %q", loadBalancerClass, conflictingController),
+			},
+		}
+	}
+	return []metav1.Condition{
+		{
+			Type:               LoadBalancerClassAvailableCondition,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             "LoadBalancerClassNotInConflict",
+			Message:            fmt.Sprintf("LoadBalancerClass %q is not used by any other AWSLoadBalancerController", loadBalancerClass),
+		},
+	}
+}
+
 func credentialsSecretConditions(secretName string, secretProvisioned bool, generation int64) []metav1.Condition {
 	var conditions []metav1.Condition
 	if secretProvisioned {
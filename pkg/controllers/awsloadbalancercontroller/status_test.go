@@ -2,6 +2,8 @@ package awsloadbalancercontroller
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -14,6 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+	"github.com/openshift/aws-load-balancer-operator/pkg/aws"
 	"github.com/openshift/aws-load-balancer-operator/pkg/utils/test"
 )
 
@@ -327,3 +330,114 @@ func TestUpdateStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterInfoConditions(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		resolveErr      error
+		vpcSource       aws.VPCSource
+		expectedStatus  metav1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:            "VPC set in the spec",
+			expectedStatus:  metav1.ConditionTrue,
+			expectedMessage: "Cluster name, AWS region and VPC resolved",
+		},
+		{
+			name:            "tagged VPC",
+			vpcSource:       aws.VPCSourceVPCTag,
+			expectedStatus:  metav1.ConditionTrue,
+			expectedMessage: "Cluster name, AWS region and VPC resolved, VPC discovered from VPCTag",
+		},
+		{
+			name:            "VPC of the tagged subnets",
+			vpcSource:       aws.VPCSourceSubnetTags,
+			expectedStatus:  metav1.ConditionTrue,
+			expectedMessage: "Cluster name, AWS region and VPC resolved, VPC discovered from SubnetTags",
+		},
+		{
+			name:            "VPC of the control plane instances",
+			vpcSource:       aws.VPCSourceControlPlaneInstances,
+			expectedStatus:  metav1.ConditionTrue,
+			expectedMessage: "Cluster name, AWS region and VPC resolved, VPC discovered from ControlPlaneInstances",
+		},
+		{
+			name:            "resolution failed",
+			resolveErr:      errors.New("no VPC found"),
+			vpcSource:       aws.VPCSourceVPCTag,
+			expectedStatus:  metav1.ConditionFalse,
+			expectedMessage: "Failed to resolve the cluster name, AWS region and VPC: no VPC found",
+		},
+		{
+			name:            "AWS API throttled",
+			resolveErr:      fmt.Errorf("failed to get VPC ID: %w", &aws.RequestError{Operation: "DescribeVpcs", Throttled: true, Transient: true, Err: errors.New("RequestLimitExceeded")}),
+			expectedStatus:  metav1.ConditionFalse,
+			expectedMessage: "AWS API requests are throttled, retrying with backoff",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conditions := clusterInfoConditions(tc.resolveErr, tc.vpcSource, 1)
+			if len(conditions) != 1 {
+				t.Fatalf("expected 1 condition, got %d", len(conditions))
+			}
+			if conditions[0].Type != ClusterInfoResolvedCondition || conditions[0].Status != tc.expectedStatus {
+				t.Errorf("expected condition %s with status %s, got %s with status %s", ClusterInfoResolvedCondition, tc.expectedStatus, conditions[0].Type, conditions[0].Status)
+			}
+			if conditions[0].Message != tc.expectedMessage {
+				t.Errorf("expected message %q, got %q", tc.expectedMessage, conditions[0].Message)
+			}
+		})
+	}
+}
+
+func TestSubnetsConditions(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		syncErr         error
+		expectedStatus  metav1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:            "subnets synced",
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  "SubnetsSynced",
+			expectedMessage: "Subnets discovered and tagged",
+		},
+		{
+			name:            "sync failed",
+			syncErr:         errors.New("no subnets found"),
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  "SubnetsSyncFailed",
+			expectedMessage: "Failed to discover and tag the subnets: no subnets found",
+		},
+		{
+			name:            "AWS API throttled",
+			syncErr:         fmt.Errorf("failed to tag subnets: %w", &aws.RequestError{Operation: "CreateTags", Throttled: true, Transient: true, Err: errors.New("RequestLimitExceeded")}),
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  "AWSAPIThrottled",
+			expectedMessage: "AWS API requests are throttled, retrying with backoff",
+		},
+		{
+			name:            "AWS API unavailable",
+			syncErr:         fmt.Errorf("failed to list subnets: %w", &aws.RequestError{Operation: "DescribeSubnets", Transient: true, Err: errors.New("request timed out")}),
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  "AWSAPIUnavailable",
+			expectedMessage: "AWS API requests failed temporarily, retrying with backoff: failed to list subnets: request timed out",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conditions := subnetsConditions(tc.syncErr, 1)
+			if len(conditions) != 1 {
+				t.Fatalf("expected 1 condition, got %d", len(conditions))
+			}
+			if conditions[0].Status != tc.expectedStatus || conditions[0].Reason != tc.expectedReason {
+				t.Errorf("expected status %s and reason %s, got status %s and reason %s", tc.expectedStatus, tc.expectedReason, conditions[0].Status, conditions[0].Reason)
+			}
+			if conditions[0].Message != tc.expectedMessage {
+				t.Errorf("expected message %q, got %q", tc.expectedMessage, conditions[0].Message)
+			}
+		})
+	}
+}
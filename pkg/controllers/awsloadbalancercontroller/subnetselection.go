@@ -0,0 +1,131 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+)
+
+const (
+	// tagFilterNameFormat is the format of the filter name which matches the value of a tag.
+	tagFilterNameFormat = "tag:%s"
+	// anyTagValue matches any value of a tag.
+	anyTagValue = "*"
+)
+
+// selectedSubnets holds the subnets selected in the spec of the controller.
+type selectedSubnets struct {
+	subnets  []ec2types.Subnet
+	public   sets.Set[string]
+	internal sets.Set[string]
+}
+
+// listSelectedSubnets lists the public and internal subnets selected in the spec of the controller.
+// The selected subnets are required to be in the given VPC and a subnet cannot be both public and internal.
+func (r *AWSLoadBalancerControllerReconciler) listSelectedSubnets(ctx context.Context, selection *albo.AWSLoadBalancerControllerSubnets, vpcID string) (*selectedSubnets, error) {
+	selected := &selectedSubnets{
+		public:   sets.New[string](),
+		internal: sets.New[string](),
+	}
+
+	public, err := r.listSubnetSelection(ctx, selection.Public, vpcID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list public subnets: %w", err)
+	}
+	internal, err := r.listSubnetSelection(ctx, selection.Internal, vpcID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list internal subnets: %w", err)
+	}
+
+	for _, s := range public {
+		selected.public.Insert(aws.ToString(s.SubnetId))
+		selected.subnets = append(selected.subnets, s)
+	}
+	for _, s := range internal {
+		subnetID := aws.ToString(s.SubnetId)
+		if selected.public.Has(subnetID) {
+			return nil, fmt.Errorf("subnet %s is selected as both public and internal", subnetID)
+		}
+		selected.internal.Insert(subnetID)
+		selected.subnets = append(selected.subnets, s)
+	}
+
+	for _, s := range selected.subnets {
+		if aws.ToString(s.VpcId) != vpcID {
+			return nil, fmt.Errorf("subnet %s belongs to VPC %s instead of the cluster VPC %s", aws.ToString(s.SubnetId), aws.ToString(s.VpcId), vpcID)
+		}
+	}
+	return selected, nil
+}
+
+// listSubnetSelection lists the subnets matching the given selection.
+// The subnets selected by tags are looked up in the given VPC.
+func (r *AWSLoadBalancerControllerReconciler) listSubnetSelection(ctx context.Context, selection *albo.AWSSubnetSelection, vpcID string) ([]ec2types.Subnet, error) {
+	if selection == nil {
+		return nil, nil
+	}
+
+	input := &ec2.DescribeSubnetsInput{}
+	if len(selection.IDs) > 0 {
+		input.SubnetIds = selection.IDs
+	} else {
+		input.Filters = []ec2types.Filter{
+			{
+				Name:   aws.String(vpcIDFilterName),
+				Values: []string{vpcID},
+			},
+		}
+		for _, tag := range selection.Tags {
+			value := tag.Value
+			if value == "" {
+				value = anyTagValue
+			}
+			input.Filters = append(input.Filters, ec2types.Filter{
+				Name:   aws.String(fmt.Sprintf(tagFilterNameFormat, tag.Key)),
+				Values: []string{value},
+			})
+		}
+	}
+
+	var subnets []ec2types.Subnet
+	subnetsPaginator := ec2.NewDescribeSubnetsPaginator(r.EC2Client, input)
+	for subnetsPaginator.HasMorePages() {
+		response, err := subnetsPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		subnets = append(subnets, response.Subnets...)
+	}
+
+	if len(selection.IDs) > 0 {
+		found := sets.New[string]()
+		for _, s := range subnets {
+			found.Insert(aws.ToString(s.SubnetId))
+		}
+		if missing := sets.New[string](selection.IDs...).Difference(found); missing.Len() > 0 {
+			return nil, fmt.Errorf("subnets %v not found", sets.List(missing))
+		}
+	}
+	if len(subnets) == 0 {
+		return nil, fmt.Errorf("no subnets matching the tags %v found in VPC %s", selection.Tags, vpcID)
+	}
+	return subnets, nil
+}
+
+// validateSubnetRoles verifies that the role tags of the selected subnets don't contradict the selection.
+func validateSubnetRoles(selected *selectedSubnets, internal, public sets.Set[string]) error {
+	if conflicting := selected.public.Intersection(internal); conflicting.Len() > 0 {
+		return fmt.Errorf("subnets %v selected as public have the tag %s", sets.List(conflicting), internalELBTagKey)
+	}
+	if conflicting := selected.internal.Intersection(public); conflicting.Len() > 0 {
+		return fmt.Errorf("subnets %v selected as internal have the tag %s", sets.List(conflicting), publicELBTagKey)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package awsloadbalancercontroller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	awstypes "github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+	"github.com/openshift/aws-load-balancer-operator/pkg/utils"
+	"github.com/openshift/aws-load-balancer-operator/pkg/utils/test"
+)
+
+func TestTagSelectedSubnets(t *testing.T) {
+	subnets := []ec2types.Subnet{
+		testSubnet("subnet-1"),
+		testSubnet("subnet-2", internalELBTagKey),
+		testSubnet("subnet-3", "example.org/tier"),
+		testSubnet("subnet-4", publicELBTagKey, tagKeyALBOTagged),
+		func() ec2types.Subnet {
+			s := testSubnet("subnet-5", "example.org/tier")
+			s.VpcId = awstypes.String("vpc-other")
+			return s
+		}(),
+	}
+
+	for _, tc := range []struct {
+		name                                string
+		selection                           *albo.AWSLoadBalancerControllerSubnets
+		vpcID                               string
+		taggingPolicy                       albo.SubnetTaggingPolicy
+		expectedPublicSubnets               []string
+		expectedInternalSubnets             []string
+		expectedTaggedSubnets               []string
+		expectedUntaggedSubnets             []string
+		expectedCreateTagOperations         []string
+		expectedCreateInternalTagOperations []string
+		expectedRemoveTagOperations         []string
+		expectedError                       string
+	}{
+		{
+			name: "auto tagging, subnets selected by IDs",
+			selection: &albo.AWSLoadBalancerControllerSubnets{
+				Public:   &albo.AWSSubnetSelection{IDs: []string{"subnet-1", "subnet-4"}},
+				Internal: &albo.AWSSubnetSelection{IDs: []string{"subnet-2"}},
+			},
+			taggingPolicy:               albo.AutoSubnetTaggingPolicy,
+			expectedPublicSubnets:       []string{"subnet-1", "subnet-4"},
+			expectedInternalSubnets:     []string{"subnet-2"},
+			expectedTaggedSubnets:       []string{"subnet-1", "subnet-4"},
+			expectedCreateTagOperations: []string{"subnet-1"},
+		},
+		{
+			name: "auto tagging, subnets selected by tags in the cluster VPC",
+			selection: &albo.AWSLoadBalancerControllerSubnets{
+				Internal: &albo.AWSSubnetSelection{Tags: []albo.AWSSubnetTagSelector{{Key: "example.org/tier"}}},
+			},
+			taggingPolicy:                       albo.AutoSubnetTaggingPolicy,
+			expectedInternalSubnets:             []string{"subnet-3"},
+			expectedTaggedSubnets:               []string{"subnet-3"},
+			expectedCreateInternalTagOperations: []string{"subnet-3"},
+		},
+		{
+			name: "auto tagging, subnets selected by tag value",
+			selection: &albo.AWSLoadBalancerControllerSubnets{
+				Internal: &albo.AWSSubnetSelection{Tags: []albo.AWSSubnetTagSelector{{Key: internalELBTagKey, Value: "1"}}},
+			},
+			taggingPolicy:           albo.AutoSubnetTaggingPolicy,
+			expectedInternalSubnets: []string{"subnet-2"},
+		},
+		{
+			name: "manual tagging, operator tags removed from selected subnets",
+			selection: &albo.AWSLoadBalancerControllerSubnets{
+				Public: &albo.AWSSubnetSelection{IDs: []string{"subnet-1", "subnet-4"}},
+			},
+			taggingPolicy:               albo.ManualSubnetTaggingPolicy,
+			expectedUntaggedSubnets:     []string{"subnet-1", "subnet-4"},
+			expectedRemoveTagOperations: []string{"subnet-4"},
+		},
+		{
+			name: "subnet not found",
+			selection: &albo.AWSLoadBalancerControllerSubnets{
+				Public: &albo.AWSSubnetSelection{IDs: []string{"subnet-1", "subnet-9"}},
+			},
+			taggingPolicy: albo.AutoSubnetTaggingPolicy,
+			expectedError: "subnets [subnet-9] not found",
+		},
+		{
+			name: "subnet from another VPC",
+			selection: &albo.AWSLoadBalancerControllerSubnets{
+				Public: &albo.AWSSubnetSelection{IDs: []string{"subnet-5"}},
+			},
+			taggingPolicy: albo.AutoSubnetTaggingPolicy,
+			expectedError: "subnet subnet-5 belongs to VPC vpc-other instead of the cluster VPC vpc-test",
+		},
+		{
+			name: "subnets selected in the VPC set in the spec",
+			selection: &albo.AWSLoadBalancerControllerSubnets{
+				Internal: &albo.AWSSubnetSelection{Tags: []albo.AWSSubnetTagSelector{{Key: "example.org/tier"}}},
+			},
+			vpcID:                               "vpc-other",
+			taggingPolicy:                       albo.AutoSubnetTaggingPolicy,
+			expectedInternalSubnets:             []string{"subnet-5"},
+			expectedTaggedSubnets:               []string{"subnet-5"},
+			expectedCreateInternalTagOperations: []string{"subnet-5"},
+		},
+		{
+			name: "VPC set in the spec not found",
+			selection: &albo.AWSLoadBalancerControllerSubnets{
+				Public: &albo.AWSSubnetSelection{IDs: []string{"subnet-1"}},
+			},
+			vpcID:         "vpc-unknown",
+			taggingPolicy: albo.AutoSubnetTaggingPolicy,
+			expectedError: "subnet subnet-1 belongs to VPC vpc-test instead of the cluster VPC vpc-unknown",
+		},
+		{
+			name: "no subnets matching the tags",
+			selection: &albo.AWSLoadBalancerControllerSubnets{
+				Public: &albo.AWSSubnetSelection{Tags: []albo.AWSSubnetTagSelector{{Key: "example.org/tier", Value: "web"}}},
+			},
+			taggingPolicy: albo.AutoSubnetTaggingPolicy,
+			expectedError: "no subnets matching the tags",
+		},
+		{
+			name: "subnet selected as both public and internal",
+			selection: &albo.AWSLoadBalancerControllerSubnets{
+				Public:   &albo.AWSSubnetSelection{IDs: []string{"subnet-1"}},
+				Internal: &albo.AWSSubnetSelection{IDs: []string{"subnet-1"}},
+			},
+			taggingPolicy: albo.AutoSubnetTaggingPolicy,
+			expectedError: "subnet subnet-1 is selected as both public and internal",
+		},
+		{
+			name: "selected role contradicts the role tag",
+			selection: &albo.AWSLoadBalancerControllerSubnets{
+				Public: &albo.AWSSubnetSelection{IDs: []string{"subnet-2"}},
+			},
+			taggingPolicy: albo.AutoSubnetTaggingPolicy,
+			expectedError: "subnets [subnet-2] selected as public have the tag kubernetes.io/role/internal-elb",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			controller := testALBC(tc.taggingPolicy)
+			controller.Spec.Subnets = tc.selection
+			controller.Spec.VPCID = tc.vpcID
+			client := fake.NewClientBuilder().WithScheme(test.Scheme).WithObjects(controller).Build()
+			ec2Client := &testEC2Client{
+				t:         t,
+				subnets:   subnets,
+				clusterID: "test-cluster",
+				VPCClient: &testVPCClient{vpcs: map[string]string{"test-cluster": "vpc-test", "other-cluster": "vpc-other"}},
+			}
+			r := &AWSLoadBalancerControllerReconciler{
+				Client:      client,
+				EC2Client:   ec2Client,
+				ClusterName: "test-cluster",
+				VPCID:       "vpc-test",
+			}
+
+			internal, public, untagged, tagged, err := r.tagSubnets(context.Background(), controller)
+			if tc.expectedError != "" {
+				if err == nil {
+					t.Fatalf("expected error %q, got nil", tc.expectedError)
+				}
+				if !strings.Contains(err.Error(), tc.expectedError) {
+					t.Errorf("expected error %q, instead got %q", tc.expectedError, err.Error())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got unexpected error: %v", err)
+			}
+
+			if !utils.EqualStrings(tc.expectedCreateTagOperations, ec2Client.taggedResources) {
+				t.Errorf("expected subnets %v to be tagged, instead got %v", tc.expectedCreateTagOperations, ec2Client.taggedResources)
+			}
+			if !utils.EqualStrings(tc.expectedCreateInternalTagOperations, ec2Client.taggedInternalResources) {
+				t.Errorf("expected subnets %v to be tagged as internal, instead got %v", tc.expectedCreateInternalTagOperations, ec2Client.taggedInternalResources)
+			}
+			if !utils.EqualStrings(tc.expectedRemoveTagOperations, ec2Client.untaggedResources) {
+				t.Errorf("expected subnets %v to have been untagged, instead got %v", tc.expectedRemoveTagOperations, ec2Client.untaggedResources)
+			}
+			if !utils.EqualStrings(tc.expectedPublicSubnets, public) {
+				t.Errorf("expected public subnets %v, got %v", tc.expectedPublicSubnets, public)
+			}
+			if !utils.EqualStrings(tc.expectedInternalSubnets, internal) {
+				t.Errorf("expected internal subnets %v, got %v", tc.expectedInternalSubnets, internal)
+			}
+			if !utils.EqualStrings(tc.expectedTaggedSubnets, tagged) {
+				t.Errorf("expected tagged subnets %v, got %v", tc.expectedTaggedSubnets, tagged)
+			}
+			if !utils.EqualStrings(tc.expectedUntaggedSubnets, untagged) {
+				t.Errorf("expected untagged subnets %v, got %v", tc.expectedUntaggedSubnets, untagged)
+			}
+		})
+	}
+}
@@ -3,7 +3,13 @@ package awsloadbalancercontroller
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -19,38 +25,93 @@ const (
 	publicELBTagKey    = "kubernetes.io/role/elb"
 	tagKeyFilterName   = "tag-key"
 	tagKeyALBOTagged   = "networking.olm.openshift.io/albo/tagged"
+	vpcIDFilterName    = "vpc-id"
+	// internetGatewayIDPrefix is the prefix of the internet gateway IDs,
+	// it distinguishes them from the other gateways like virtual private gateways.
+	internetGatewayIDPrefix = "igw-"
 )
 
-// tagSubnets will add detect the subnets of the cluster and then tag them appropriately. It then writes the detected
-// subnet IDs into the status along with their tagged roles.
-func (r *AWSLoadBalancerControllerReconciler) tagSubnets(ctx context.Context, controller *albo.AWSLoadBalancerController) (internalSubnets, publicSubnets, untaggedSubnets, taggedSubnets []string, err error) {
-	// list the subnets which are tagged as owned by the cluster
-	subnetsPaginator := ec2.NewDescribeSubnetsPaginator(r.EC2Client, &ec2.DescribeSubnetsInput{
-		Filters: []ec2types.Filter{
-			{
-				Name:   aws.String(tagKeyFilterName),
-				Values: []string{fmt.Sprintf(clusterOwnedTagKey, r.ClusterName)},
-			},
-		},
-	})
+// subnetSyncTracker keeps the time of the last subnet synchronization of each instance.
+type subnetSyncTracker struct {
+	lock     sync.Mutex
+	lastSync map[string]time.Time
+}
 
-	var (
-		subnets  []ec2types.Subnet
-		response *ec2.DescribeSubnetsOutput
-	)
-	for subnetsPaginator.HasMorePages() {
-		response, err = subnetsPaginator.NextPage(ctx)
-		if err != nil {
-			err = fmt.Errorf("failed to list subnets for cluster id %s: %w", r.ClusterName, err)
-			return
-		}
-		subnets = append(subnets, response.Subnets...)
+// synced records the synchronization of the subnets of the given instance.
+func (t *subnetSyncTracker) synced(name string, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.lastSync == nil {
+		t.lastSync = make(map[string]time.Time)
 	}
+	t.lastSync[name] = now
+}
 
-	if len(subnets) == 0 {
-		err = fmt.Errorf("no subnets with tag %s found", fmt.Sprintf(clusterOwnedTagKey, r.ClusterName))
+// forget removes the given instance from the tracker.
+func (t *subnetSyncTracker) forget(name string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.lastSync, name)
+}
+
+// last returns the time of the last synchronization of the subnets of the given instance
+// and whether the subnets were synchronized since the operator started.
+func (t *subnetSyncTracker) last(name string) (time.Time, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	last, ok := t.lastSync[name]
+	return last, ok
+}
+
+// subnetsNeedSync checks whether the subnets of the controller have to be re-discovered and re-tagged.
+// This is the case when the subnets have not been written into the status yet, when the tagging policy has changed,
+// when the spec changed or the last synchronization failed, or when the resync interval elapsed since the last synchronization.
+// The subnets are always synchronized once after the operator starts so that the changes done while it was not running are caught.
+func (r *AWSLoadBalancerControllerReconciler) subnetsNeedSync(controller *albo.AWSLoadBalancerController, now time.Time) bool {
+	if controller.Status.Subnets == nil || controller.Spec.SubnetTagging != controller.Status.Subnets.SubnetTagging {
+		return true
+	}
+	// the subnet selection may have changed with the spec
+	if cond := meta.FindStatusCondition(controller.Status.Conditions, SubnetsAvailableCondition); cond != nil && (cond.Status != metav1.ConditionTrue || cond.ObservedGeneration != controller.Generation) {
+		return true
+	}
+	if r.SubnetResyncInterval <= 0 {
+		return false
+	}
+	last, synced := r.subnetSyncs.last(controller.Name)
+	return !synced || now.Sub(last) >= r.SubnetResyncInterval
+}
+
+// subnetResyncAfter returns the delay after which the subnets of the given instance have to be synchronized again.
+// Zero is returned if the periodic synchronization is disabled.
+func (r *AWSLoadBalancerControllerReconciler) subnetResyncAfter(name string, now time.Time) time.Duration {
+	if r.SubnetResyncInterval <= 0 {
+		return 0
+	}
+	last, synced := r.subnetSyncs.last(name)
+	if !synced {
+		return r.SubnetResyncInterval
+	}
+	if remaining := r.SubnetResyncInterval - now.Sub(last); remaining > 0 {
+		return remaining
+	}
+	// the resync is overdue, requeue shortly
+	return time.Second
+}
+
+// tagSubnets will add detect the subnets of the cluster and then tag them appropriately. It then writes the detected
+// subnet IDs into the status along with their tagged roles. When the subnets are selected in the spec
+// only the selected subnets are considered and their role is taken from the selection.
+func (r *AWSLoadBalancerControllerReconciler) tagSubnets(ctx context.Context, controller *albo.AWSLoadBalancerController) (internalSubnets, publicSubnets, untaggedSubnets, taggedSubnets []string, err error) {
+	subnets, selected, err := r.listControllerSubnets(ctx, controller)
+	if err != nil {
 		return
 	}
+	if controller.Spec.VPCID != "" {
+		if err = r.validateVPC(ctx, controller.Spec.VPCID, subnets); err != nil {
+			return
+		}
+	}
 
 	var (
 		internal sets.Set[string]
@@ -64,60 +125,53 @@ func (r *AWSLoadBalancerControllerReconciler) tagSubnets(ctx context.Context, co
 		err = fmt.Errorf("failed to classify subnets of cluster %s: %w", r.ClusterName, err)
 		return
 	}
+	if selected != nil {
+		if err = validateSubnetRoles(selected, internal, public); err != nil {
+			return
+		}
+	}
 
 	switch controller.Spec.SubnetTagging {
 	case albo.AutoSubnetTaggingPolicy:
-		// in OpenShift all private subnets are tagged. So assume any untagged subnets are public
-		// TODO: process the subnets based on whether they have attached internet gateways
+		// the role of the untagged subnets is taken from the selection or
+		// determined by their routes to the internet
 		if untagged.Len() > 0 {
-			_, err = r.EC2Client.CreateTags(ctx, &ec2.CreateTagsInput{
-				Resources: sets.List(untagged),
-				Tags: []ec2types.Tag{
-					{
-						Key:   aws.String(publicELBTagKey),
-						Value: aws.String("1"),
-					},
-					{
-						Key:   aws.String(tagKeyALBOTagged),
-						Value: aws.String("1"),
-					},
-				},
-			})
+			var untaggedPublic, untaggedInternal sets.Set[string]
+			if selected != nil {
+				untaggedPublic, untaggedInternal = untagged.Intersection(selected.public), untagged.Intersection(selected.internal)
+			} else {
+				untaggedPublic, untaggedInternal, err = r.classifySubnetsByRouteTables(ctx, subnets, untagged)
+			}
 			if err != nil {
-				err = fmt.Errorf("failed to tag subnets %v: %w", untaggedSubnets, err)
+				err = fmt.Errorf("failed to determine the role of the untagged subnets %v: %w", sets.List(untagged), err)
+				return
+			}
+			if err = r.addSubnetTags(ctx, controller, sets.List(untaggedPublic), publicELBTagKey); err != nil {
+				return
+			}
+			if err = r.addSubnetTags(ctx, controller, sets.List(untaggedInternal), internalELBTagKey); err != nil {
 				return
 			}
+			public = public.Union(untaggedPublic)
+			internal = internal.Union(untaggedInternal)
 		}
-		// the untagged subnets are now public subnets
-		public = public.Union(untagged)
 		// marked the untagged subnets as now tagged
 		tagged = tagged.Union(untagged)
 		// there are no untagged subnets now
 		untagged = sets.New[string]()
 	case albo.ManualSubnetTaggingPolicy:
 		// if the tagging policy was changed to Manual then remove tags from previously tagged subnets
-		if tagged.Len() > 0 {
-			// when values are not specified with the tag name the tag value is not considered during tag removal
-			_, err = r.EC2Client.DeleteTags(ctx, &ec2.DeleteTagsInput{
-				Resources: sets.List(tagged),
-				Tags: []ec2types.Tag{
-					{
-						Key: aws.String(publicELBTagKey),
-					},
-					{
-						Key: aws.String(tagKeyALBOTagged),
-					},
-				},
-			})
-			if err != nil {
-				err = fmt.Errorf("failed to remove tags from currently tagged subnets %v: %w", taggedSubnets, err)
-				return
-			}
+		if err = r.removeSubnetTags(ctx, controller, sets.List(tagged.Intersection(public)), publicELBTagKey); err != nil {
+			return
+		}
+		if err = r.removeSubnetTags(ctx, controller, sets.List(tagged.Intersection(internal)), internalELBTagKey); err != nil {
+			return
 		}
 		// the previously tagged subnets are now untagged
 		untagged = untagged.Union(tagged)
-		// removed the subnets which were untagged from the public subnets
+		// removed the subnets which were untagged from the public and internal subnets
 		public = public.Difference(tagged)
+		internal = internal.Difference(tagged)
 		// set the tagged subnets to empty
 		tagged = sets.New[string]()
 	default:
@@ -131,6 +185,189 @@ func (r *AWSLoadBalancerControllerReconciler) tagSubnets(ctx context.Context, co
 	return
 }
 
+// untagSubnets removes the tags added by the operator from the subnets of the cluster
+// or from the subnets selected in the spec of the controller.
+// It returns the IDs of the subnets which were untagged.
+func (r *AWSLoadBalancerControllerReconciler) untagSubnets(ctx context.Context, controller *albo.AWSLoadBalancerController) ([]string, error) {
+	subnets, _, err := r.listControllerSubnets(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+	internal, public, tagged, _, err := classifySubnets(subnets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to classify subnets of cluster %s: %w", r.ClusterName, err)
+	}
+	if err := r.removeSubnetTags(ctx, controller, sets.List(tagged.Intersection(public)), publicELBTagKey); err != nil {
+		return nil, err
+	}
+	if err := r.removeSubnetTags(ctx, controller, sets.List(tagged.Intersection(internal)), internalELBTagKey); err != nil {
+		return nil, err
+	}
+	return sets.List(tagged), nil
+}
+
+// listControllerSubnets lists the subnets selected in the spec of the controller
+// or the subnets of the cluster if no subnets are selected.
+// The selection is returned as well if the subnets are selected in the spec.
+func (r *AWSLoadBalancerControllerReconciler) listControllerSubnets(ctx context.Context, controller *albo.AWSLoadBalancerController) ([]ec2types.Subnet, *selectedSubnets, error) {
+	if controller.Spec.Subnets != nil {
+		selected, err := r.listSelectedSubnets(ctx, controller.Spec.Subnets, r.controllerVPCID(controller))
+		if err != nil {
+			return nil, nil, err
+		}
+		return selected.subnets, selected, nil
+	}
+	subnets, err := r.listClusterSubnets(ctx)
+	return subnets, nil, err
+}
+
+// listClusterSubnets lists the subnets which are tagged as owned by the cluster.
+func (r *AWSLoadBalancerControllerReconciler) listClusterSubnets(ctx context.Context) ([]ec2types.Subnet, error) {
+	subnetsPaginator := ec2.NewDescribeSubnetsPaginator(r.EC2Client, &ec2.DescribeSubnetsInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String(tagKeyFilterName),
+				Values: []string{fmt.Sprintf(clusterOwnedTagKey, r.ClusterName)},
+			},
+		},
+	})
+
+	var subnets []ec2types.Subnet
+	for subnetsPaginator.HasMorePages() {
+		response, err := subnetsPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list subnets for cluster id %s: %w", r.ClusterName, err)
+		}
+		subnets = append(subnets, response.Subnets...)
+	}
+
+	if len(subnets) == 0 {
+		return nil, fmt.Errorf("no subnets with tag %s found", fmt.Sprintf(clusterOwnedTagKey, r.ClusterName))
+	}
+	return subnets, nil
+}
+
+// addSubnetTags adds the given role tag and the operator's tag to the given subnets.
+func (r *AWSLoadBalancerControllerReconciler) addSubnetTags(ctx context.Context, controller *albo.AWSLoadBalancerController, subnetIDs []string, roleTagKey string) error {
+	if len(subnetIDs) == 0 {
+		return nil
+	}
+	_, err := r.EC2Client.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: subnetIDs,
+		Tags: []ec2types.Tag{
+			{
+				Key:   aws.String(roleTagKey),
+				Value: aws.String("1"),
+			},
+			{
+				Key:   aws.String(tagKeyALBOTagged),
+				Value: aws.String("1"),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag subnets %v with %s: %w", subnetIDs, roleTagKey, err)
+	}
+	r.eventf(controller, corev1.EventTypeNormal, subnetsTaggedEventReason, "Tagged subnets %v with %s", subnetIDs, roleTagKey)
+	return nil
+}
+
+// removeSubnetTags removes the given role tag and the operator's tag from the given subnets.
+func (r *AWSLoadBalancerControllerReconciler) removeSubnetTags(ctx context.Context, controller *albo.AWSLoadBalancerController, subnetIDs []string, roleTagKey string) error {
+	if len(subnetIDs) == 0 {
+		return nil
+	}
+	// when values are not specified with the tag name the tag value is not considered during tag removal
+	_, err := r.EC2Client.DeleteTags(ctx, &ec2.DeleteTagsInput{
+		Resources: subnetIDs,
+		Tags: []ec2types.Tag{
+			{
+				Key: aws.String(roleTagKey),
+			},
+			{
+				Key: aws.String(tagKeyALBOTagged),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove tags from currently tagged subnets %v: %w", subnetIDs, err)
+	}
+	r.eventf(controller, corev1.EventTypeNormal, subnetsUntaggedEventReason, "Removed tag %s from subnets %v", roleTagKey, subnetIDs)
+	return nil
+}
+
+// classifySubnetsByRouteTables splits the given subnets into public and internal subnets.
+// A subnet is public if its route table has an active route to an internet gateway, it's internal otherwise.
+// The subnets without an explicit route table association use the main route table of their VPC.
+func (r *AWSLoadBalancerControllerReconciler) classifySubnetsByRouteTables(ctx context.Context, subnets []ec2types.Subnet, subnetIDs sets.Set[string]) (sets.Set[string], sets.Set[string], error) {
+	vpcIDs := sets.New[string]()
+	for _, s := range subnets {
+		if subnetIDs.Has(aws.ToString(s.SubnetId)) {
+			vpcIDs.Insert(aws.ToString(s.VpcId))
+		}
+	}
+
+	routeTablesPaginator := ec2.NewDescribeRouteTablesPaginator(r.EC2Client, &ec2.DescribeRouteTablesInput{
+		Filters: []ec2types.Filter{
+			{
+				Name:   aws.String(vpcIDFilterName),
+				Values: sets.List(vpcIDs),
+			},
+		},
+	})
+
+	var (
+		// route table of the explicitly associated subnets
+		subnetRouteTables = make(map[string]ec2types.RouteTable)
+		// main route table of each VPC
+		mainRouteTables = make(map[string]ec2types.RouteTable)
+	)
+	for routeTablesPaginator.HasMorePages() {
+		response, err := routeTablesPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list route tables of VPCs %v: %w", sets.List(vpcIDs), err)
+		}
+		for _, rt := range response.RouteTables {
+			for _, assoc := range rt.Associations {
+				if aws.ToBool(assoc.Main) {
+					mainRouteTables[aws.ToString(rt.VpcId)] = rt
+				}
+				if assoc.SubnetId != nil {
+					subnetRouteTables[aws.ToString(assoc.SubnetId)] = rt
+				}
+			}
+		}
+	}
+
+	public, internal := sets.New[string](), sets.New[string]()
+	for _, s := range subnets {
+		subnetID := aws.ToString(s.SubnetId)
+		if !subnetIDs.Has(subnetID) {
+			continue
+		}
+		rt, found := subnetRouteTables[subnetID]
+		if !found {
+			rt, found = mainRouteTables[aws.ToString(s.VpcId)]
+		}
+		if found && hasInternetGatewayRoute(rt) {
+			public.Insert(subnetID)
+		} else {
+			internal.Insert(subnetID)
+		}
+	}
+	return public, internal, nil
+}
+
+// hasInternetGatewayRoute checks whether the route table has an active route to an internet gateway.
+func hasInternetGatewayRoute(rt ec2types.RouteTable) bool {
+	for _, route := range rt.Routes {
+		if strings.HasPrefix(aws.ToString(route.GatewayId), internetGatewayIDPrefix) && route.State != ec2types.RouteStateBlackhole {
+			return true
+		}
+	}
+	return false
+}
+
 func classifySubnets(subnets []ec2types.Subnet) (sets.Set[string], sets.Set[string], sets.Set[string], sets.Set[string], error) {
 	var (
 		internal = sets.New[string]()
@@ -149,13 +386,12 @@ func classifySubnets(subnets []ec2types.Subnet) (sets.Set[string], sets.Set[stri
 				return nil, nil, nil, nil, fmt.Errorf("subnet %s has both tags with keys %s and %s", subnetID, internalELBTagKey, publicELBTagKey)
 			}
 			public.Insert(subnetID)
-			// only check operator tagging if the subnet is a public subnet
-			if hasTag(s.Tags, tagKeyALBOTagged) {
-				tagged.Insert(subnetID)
-			}
 		}
 		if !internal.Has(subnetID) && !public.Has(subnetID) {
 			untagged.Insert(subnetID)
+		} else if hasTag(s.Tags, tagKeyALBOTagged) {
+			// only check operator tagging if the subnet has a role tag
+			tagged.Insert(subnetID)
 		}
 	}
 
@@ -6,17 +6,22 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 
 	awstypes "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/google/go-cmp/cmp"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
 	"github.com/openshift/aws-load-balancer-operator/pkg/aws"
+	ec2fake "github.com/openshift/aws-load-balancer-operator/pkg/aws/fake"
 	"github.com/openshift/aws-load-balancer-operator/pkg/utils"
 	"github.com/openshift/aws-load-balancer-operator/pkg/utils/test"
 )
@@ -66,14 +71,21 @@ func TestClassifySubnet(t *testing.T) {
 			expectedUntaggedSubnets: []string{"subnet-3"},
 		},
 		{
-			name: "ignore internal subnets with ALBO tag",
+			name: "tagged internal subnets",
 			inputSubnets: []ec2types.Subnet{
 				testSubnet("subnet-1", publicELBTagKey, tagKeyALBOTagged),
 				testSubnet("subnet-2", internalELBTagKey, tagKeyALBOTagged),
 			},
 			expectedInternalSubnets: []string{"subnet-2"},
 			expectedPublicSubnets:   []string{"subnet-1"},
-			expectedTaggedSubnets:   []string{"subnet-1"},
+			expectedTaggedSubnets:   []string{"subnet-1", "subnet-2"},
+		},
+		{
+			name: "ignore untagged subnets with ALBO tag",
+			inputSubnets: []ec2types.Subnet{
+				testSubnet("subnet-1", tagKeyALBOTagged),
+			},
+			expectedUntaggedSubnets: []string{"subnet-1"},
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
@@ -107,6 +119,7 @@ func TestClassifySubnet(t *testing.T) {
 func testSubnet(name string, tagKeys ...string) ec2types.Subnet {
 	s := ec2types.Subnet{
 		SubnetId: awstypes.String(name),
+		VpcId:    awstypes.String("vpc-test"),
 	}
 	for _, k := range tagKeys {
 		s.Tags = append(s.Tags, ec2types.Tag{
@@ -117,18 +130,43 @@ func testSubnet(name string, tagKeys ...string) ec2types.Subnet {
 	return s
 }
 
+func testRouteTable(main bool, subnetIDs []string, gatewayIDs ...string) ec2types.RouteTable {
+	rt := ec2types.RouteTable{
+		RouteTableId: awstypes.String("rtb-test"),
+		VpcId:        awstypes.String("vpc-test"),
+	}
+	if main {
+		rt.Associations = append(rt.Associations, ec2types.RouteTableAssociation{Main: awstypes.Bool(true)})
+	}
+	for _, id := range subnetIDs {
+		rt.Associations = append(rt.Associations, ec2types.RouteTableAssociation{SubnetId: awstypes.String(id)})
+	}
+	for _, id := range gatewayIDs {
+		rt.Routes = append(rt.Routes, ec2types.Route{
+			DestinationCidrBlock: awstypes.String("0.0.0.0/0"),
+			GatewayId:            awstypes.String(id),
+			State:                ec2types.RouteStateActive,
+		})
+	}
+	return rt
+}
+
 func TestTagSubnets(t *testing.T) {
 	for _, tc := range []struct {
-		name                        string
-		currentSubnets              []ec2types.Subnet
-		statusUntaggedSubnets       []string
-		expectedTaggedSubnets       []string
-		expectedUntaggedSubnets     []string
-		taggingPolicy               albo.SubnetTaggingPolicy
-		expectedPublicSubnets       []string
-		expectedInternalSubnets     []string
-		expectedCreateTagOperations []string
-		expectedRemoveTagOperations []string
+		name                                string
+		currentSubnets                      []ec2types.Subnet
+		routeTables                         []ec2types.RouteTable
+		statusUntaggedSubnets               []string
+		expectedTaggedSubnets               []string
+		expectedUntaggedSubnets             []string
+		taggingPolicy                       albo.SubnetTaggingPolicy
+		expectedPublicSubnets               []string
+		expectedInternalSubnets             []string
+		expectedCreateTagOperations         []string
+		expectedCreateInternalTagOperations []string
+		expectedRemoveTagOperations         []string
+		expectedRemoveInternalTagOperations []string
+		expectedEvents                      []string
 	}{
 		{
 			name: "auto tagging, no preexisting tagged subnets",
@@ -137,11 +175,75 @@ func TestTagSubnets(t *testing.T) {
 				testSubnet("subnet-2", internalELBTagKey),
 				testSubnet("subnet-3", publicELBTagKey),
 			},
+			routeTables: []ec2types.RouteTable{
+				testRouteTable(true, nil, "igw-1"),
+			},
 			taggingPolicy:               albo.AutoSubnetTaggingPolicy,
 			expectedTaggedSubnets:       []string{"subnet-1"},
 			expectedPublicSubnets:       []string{"subnet-1", "subnet-3"},
 			expectedInternalSubnets:     []string{"subnet-2"},
 			expectedCreateTagOperations: []string{"subnet-1"},
+			expectedEvents:              []string{"Normal SubnetsTagged Tagged subnets [subnet-1] with kubernetes.io/role/elb"},
+		},
+		{
+			name: "auto tagging, untagged subnets classified by route tables",
+			currentSubnets: []ec2types.Subnet{
+				testSubnet("subnet-1"),
+				testSubnet("subnet-2"),
+				testSubnet("subnet-3"),
+				testSubnet("subnet-4"),
+			},
+			routeTables: []ec2types.RouteTable{
+				// main route table without internet access
+				testRouteTable(true, nil, "vgw-1"),
+				testRouteTable(false, []string{"subnet-1"}, "igw-1"),
+				testRouteTable(false, []string{"subnet-2"}),
+			},
+			taggingPolicy:                       albo.AutoSubnetTaggingPolicy,
+			expectedTaggedSubnets:               []string{"subnet-1", "subnet-2", "subnet-3", "subnet-4"},
+			expectedPublicSubnets:               []string{"subnet-1"},
+			expectedInternalSubnets:             []string{"subnet-2", "subnet-3", "subnet-4"},
+			expectedCreateTagOperations:         []string{"subnet-1"},
+			expectedCreateInternalTagOperations: []string{"subnet-2", "subnet-3", "subnet-4"},
+			expectedEvents: []string{
+				"Normal SubnetsTagged Tagged subnets [subnet-1] with kubernetes.io/role/elb",
+				"Normal SubnetsTagged Tagged subnets [subnet-2 subnet-3 subnet-4] with kubernetes.io/role/internal-elb",
+			},
+		},
+		{
+			name: "auto tagging, blackhole route to internet gateway",
+			currentSubnets: []ec2types.Subnet{
+				testSubnet("subnet-1"),
+			},
+			routeTables: []ec2types.RouteTable{
+				func() ec2types.RouteTable {
+					rt := testRouteTable(false, []string{"subnet-1"}, "igw-1")
+					rt.Routes[0].State = ec2types.RouteStateBlackhole
+					return rt
+				}(),
+			},
+			taggingPolicy:                       albo.AutoSubnetTaggingPolicy,
+			expectedTaggedSubnets:               []string{"subnet-1"},
+			expectedInternalSubnets:             []string{"subnet-1"},
+			expectedCreateInternalTagOperations: []string{"subnet-1"},
+			expectedEvents:                      []string{"Normal SubnetsTagged Tagged subnets [subnet-1] with kubernetes.io/role/internal-elb"},
+		},
+		{
+			name: "manual tagging, with preexisting tagged internal subnets",
+			currentSubnets: []ec2types.Subnet{
+				testSubnet("subnet-1", publicELBTagKey, tagKeyALBOTagged),
+				testSubnet("subnet-2", internalELBTagKey, tagKeyALBOTagged),
+				testSubnet("subnet-3", internalELBTagKey),
+			},
+			taggingPolicy:                       albo.ManualSubnetTaggingPolicy,
+			expectedInternalSubnets:             []string{"subnet-3"},
+			expectedRemoveTagOperations:         []string{"subnet-1"},
+			expectedRemoveInternalTagOperations: []string{"subnet-2"},
+			expectedUntaggedSubnets:             []string{"subnet-1", "subnet-2"},
+			expectedEvents: []string{
+				"Normal SubnetsUntagged Removed tag kubernetes.io/role/elb from subnets [subnet-1]",
+				"Normal SubnetsUntagged Removed tag kubernetes.io/role/internal-elb from subnets [subnet-2]",
+			},
 		},
 		{
 			name: "auto tagging, with preexisting tagged subnets",
@@ -179,6 +281,7 @@ func TestTagSubnets(t *testing.T) {
 			expectedRemoveTagOperations: []string{"subnet-1"},
 			expectedUntaggedSubnets:     []string{"subnet-1"},
 			expectedPublicSubnets:       []string{"subnet-3"},
+			expectedEvents:              []string{"Normal SubnetsUntagged Removed tag kubernetes.io/role/elb from subnets [subnet-1]"},
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
@@ -187,14 +290,17 @@ func TestTagSubnets(t *testing.T) {
 				controller,
 			).Build()
 			ec2Client := &testEC2Client{
-				t:         t,
-				subnets:   tc.currentSubnets,
-				clusterID: "test-cluster",
+				t:           t,
+				subnets:     tc.currentSubnets,
+				routeTables: tc.routeTables,
+				clusterID:   "test-cluster",
 			}
+			recorder := record.NewFakeRecorder(10)
 			r := &AWSLoadBalancerControllerReconciler{
 				Client:      client,
 				EC2Client:   ec2Client,
 				ClusterName: "test-cluster",
+				Recorder:    recorder,
 			}
 
 			internal, public, untagged, tagged, err := r.tagSubnets(context.Background(), controller)
@@ -211,6 +317,14 @@ func TestTagSubnets(t *testing.T) {
 				t.Errorf("expected subnets %v to have been untagged, instead got %v", tc.expectedRemoveTagOperations, ec2Client.untaggedResources)
 			}
 
+			if !utils.EqualStrings(tc.expectedCreateInternalTagOperations, ec2Client.taggedInternalResources) {
+				t.Errorf("expected subnets %v to be tagged as internal, instead got %v", tc.expectedCreateInternalTagOperations, ec2Client.taggedInternalResources)
+			}
+
+			if !utils.EqualStrings(tc.expectedRemoveInternalTagOperations, ec2Client.untaggedInternalResources) {
+				t.Errorf("expected subnets %v to have been untagged as internal, instead got %v", tc.expectedRemoveInternalTagOperations, ec2Client.untaggedInternalResources)
+			}
+
 			if !utils.EqualStrings(tc.expectedPublicSubnets, public) {
 				t.Errorf("expected public subnets %v, got %v", tc.expectedPublicSubnets, public)
 			}
@@ -223,6 +337,112 @@ func TestTagSubnets(t *testing.T) {
 			if !utils.EqualStrings(tc.expectedUntaggedSubnets, untagged) {
 				t.Errorf("expected untagged subnets %v, got %v", tc.expectedUntaggedSubnets, untagged)
 			}
+			if diff := cmp.Diff(tc.expectedEvents, recordedEvents(recorder)); diff != "" {
+				t.Errorf("unexpected events (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSubnetsNeedSync(t *testing.T) {
+	now := time.Now()
+	for _, tc := range []struct {
+		name           string
+		controller     *albo.AWSLoadBalancerController
+		resyncInterval time.Duration
+		lastSync       *time.Time
+		expectedSync   bool
+		expectedAfter  time.Duration
+	}{
+		{
+			name:           "subnets not in status",
+			controller:     &albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+			resyncInterval: 10 * time.Minute,
+			lastSync:       &now,
+			expectedSync:   true,
+			expectedAfter:  10 * time.Minute,
+		},
+		{
+			name: "tagging policy changed",
+			controller: func() *albo.AWSLoadBalancerController {
+				c := testALBC(albo.ManualSubnetTaggingPolicy)
+				c.Spec.SubnetTagging = albo.AutoSubnetTaggingPolicy
+				return c
+			}(),
+			lastSync:     &now,
+			expectedSync: true,
+		},
+		{
+			name: "spec changed since the last synchronization",
+			controller: func() *albo.AWSLoadBalancerController {
+				c := testALBC(albo.AutoSubnetTaggingPolicy)
+				c.Generation = 2
+				c.Status.Conditions = subnetsConditions(nil, 1)
+				return c
+			}(),
+			lastSync:     &now,
+			expectedSync: true,
+		},
+		{
+			name: "last synchronization failed",
+			controller: func() *albo.AWSLoadBalancerController {
+				c := testALBC(albo.AutoSubnetTaggingPolicy)
+				c.Status.Conditions = subnetsConditions(errors.New("no subnets found"), 0)
+				return c
+			}(),
+			lastSync:     &now,
+			expectedSync: true,
+		},
+		{
+			name: "spec unchanged since the last synchronization",
+			controller: func() *albo.AWSLoadBalancerController {
+				c := testALBC(albo.AutoSubnetTaggingPolicy)
+				c.Status.Conditions = subnetsConditions(nil, 0)
+				return c
+			}(),
+			lastSync:     &now,
+			expectedSync: false,
+		},
+		{
+			name:         "resync disabled",
+			controller:   testALBC(albo.AutoSubnetTaggingPolicy),
+			expectedSync: false,
+		},
+		{
+			name:           "not synced since the operator started",
+			controller:     testALBC(albo.AutoSubnetTaggingPolicy),
+			resyncInterval: 10 * time.Minute,
+			expectedSync:   true,
+			expectedAfter:  10 * time.Minute,
+		},
+		{
+			name:           "resync interval not elapsed",
+			controller:     testALBC(albo.AutoSubnetTaggingPolicy),
+			resyncInterval: 10 * time.Minute,
+			lastSync:       ptr.To(now.Add(-4 * time.Minute)),
+			expectedSync:   false,
+			expectedAfter:  6 * time.Minute,
+		},
+		{
+			name:           "resync interval elapsed",
+			controller:     testALBC(albo.AutoSubnetTaggingPolicy),
+			resyncInterval: 10 * time.Minute,
+			lastSync:       ptr.To(now.Add(-11 * time.Minute)),
+			expectedSync:   true,
+			expectedAfter:  time.Second,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &AWSLoadBalancerControllerReconciler{SubnetResyncInterval: tc.resyncInterval}
+			if tc.lastSync != nil {
+				r.subnetSyncs.synced(tc.controller.Name, *tc.lastSync)
+			}
+			if sync := r.subnetsNeedSync(tc.controller, now); sync != tc.expectedSync {
+				t.Errorf("expected subnets sync %v, got %v", tc.expectedSync, sync)
+			}
+			if after := r.subnetResyncAfter(tc.controller.Name, now); after != tc.expectedAfter {
+				t.Errorf("expected resync after %v, got %v", tc.expectedAfter, after)
+			}
 		})
 	}
 }
@@ -239,12 +459,159 @@ func testALBC(taggingPolicy albo.SubnetTaggingPolicy) *albo.AWSLoadBalancerContr
 	}
 }
 
+// newFakeEC2 returns a fake EC2 with a public, a private and an unknown subnet of the test cluster.
+// The public subnet routes to an internet gateway, the private subnet uses the main route table of the VPC.
+func newFakeEC2() *ec2fake.EC2 {
+	clusterTag := ec2types.Tag{Key: awstypes.String("kubernetes.io/cluster/test-cluster"), Value: awstypes.String("shared")}
+	f := ec2fake.NewEC2()
+	f.PageSize = 1
+	f.AddVPC(ec2types.Vpc{VpcId: awstypes.String("vpc-1")})
+	for _, id := range []string{"subnet-public", "subnet-private"} {
+		f.AddSubnet(ec2types.Subnet{SubnetId: awstypes.String(id), VpcId: awstypes.String("vpc-1"), Tags: []ec2types.Tag{clusterTag}})
+	}
+	f.AddSubnet(ec2types.Subnet{SubnetId: awstypes.String("subnet-other-cluster"), VpcId: awstypes.String("vpc-1")})
+	f.AddRouteTable(ec2types.RouteTable{
+		RouteTableId: awstypes.String("rtb-public"),
+		VpcId:        awstypes.String("vpc-1"),
+		Associations: []ec2types.RouteTableAssociation{{SubnetId: awstypes.String("subnet-public")}},
+		Routes:       []ec2types.Route{{GatewayId: awstypes.String("igw-1"), State: ec2types.RouteStateActive}},
+	})
+	f.AddRouteTable(ec2types.RouteTable{
+		RouteTableId: awstypes.String("rtb-main"),
+		VpcId:        awstypes.String("vpc-1"),
+		Associations: []ec2types.RouteTableAssociation{{Main: awstypes.Bool(true)}},
+		Routes:       []ec2types.Route{{NatGatewayId: awstypes.String("nat-1"), State: ec2types.RouteStateActive}},
+	})
+	return f
+}
+
+func TestTagSubnetsWithFakeEC2(t *testing.T) {
+	expectedPublicTags := map[string]string{"kubernetes.io/cluster/test-cluster": "shared", publicELBTagKey: "1", tagKeyALBOTagged: "1"}
+	expectedPrivateTags := map[string]string{"kubernetes.io/cluster/test-cluster": "shared", internalELBTagKey: "1", tagKeyALBOTagged: "1"}
+
+	for _, tc := range []struct {
+		name string
+		// setup scripts the faults of the fake EC2
+		setup func(f *ec2fake.EC2)
+		// expectedErrors are the errors of the successive syncs before the tagging succeeds
+		expectedErrors    []string
+		expectedThrottled bool
+		expectedTags      map[string]map[string]string
+	}{
+		{
+			name: "untagged subnets classified by route tables",
+			expectedTags: map[string]map[string]string{
+				"subnet-public":        expectedPublicTags,
+				"subnet-private":       expectedPrivateTags,
+				"subnet-other-cluster": {},
+			},
+		},
+		{
+			name: "throttled subnets listing",
+			setup: func(f *ec2fake.EC2) {
+				f.InjectFault(ec2fake.Fault{Operation: "DescribeSubnets", Err: ec2fake.ThrottlingError(), Count: 2})
+			},
+			expectedErrors:    []string{"RequestLimitExceeded", "RequestLimitExceeded"},
+			expectedThrottled: true,
+			expectedTags: map[string]map[string]string{
+				"subnet-public":  expectedPublicTags,
+				"subnet-private": expectedPrivateTags,
+			},
+		},
+		{
+			name: "partially failed tagging",
+			setup: func(f *ec2fake.EC2) {
+				f.InjectFault(ec2fake.Fault{Operation: "CreateTags", Err: ec2fake.ThrottlingError(), Count: 1, ResourceIDs: []string{"subnet-private"}})
+			},
+			expectedErrors:    []string{"RequestLimitExceeded"},
+			expectedThrottled: true,
+			expectedTags: map[string]map[string]string{
+				"subnet-public":  expectedPublicTags,
+				"subnet-private": expectedPrivateTags,
+			},
+		},
+		{
+			name: "eventually consistent tags",
+			setup: func(f *ec2fake.EC2) {
+				f.SetTagPropagationDelay(5)
+			},
+			expectedTags: map[string]map[string]string{
+				"subnet-public":  expectedPublicTags,
+				"subnet-private": expectedPrivateTags,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			controller := testALBC(albo.AutoSubnetTaggingPolicy)
+			ec2Client := newFakeEC2()
+			if tc.setup != nil {
+				tc.setup(ec2Client)
+			}
+			r := &AWSLoadBalancerControllerReconciler{
+				Client:      fake.NewClientBuilder().WithScheme(test.Scheme).WithObjects(controller).Build(),
+				EC2Client:   aws.NewInstrumentedClient(ec2Client),
+				ClusterName: "test-cluster",
+			}
+
+			// the subnets are synced again until the faults are exhausted, like the requeued reconciliations do
+			var (
+				internal, public, untagged, tagged []string
+				err                                error
+			)
+			for i := 0; i <= len(tc.expectedErrors); i++ {
+				internal, public, untagged, tagged, err = r.tagSubnets(context.Background(), controller)
+				if i == len(tc.expectedErrors) {
+					break
+				}
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErrors[i]) {
+					t.Fatalf("expected sync %d to fail with %q, got %v", i+1, tc.expectedErrors[i], err)
+				}
+				if aws.IsThrottled(err) != tc.expectedThrottled {
+					t.Errorf("expected throttled to be %t for %v", tc.expectedThrottled, err)
+				}
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !utils.EqualStrings([]string{"subnet-public"}, public) || !utils.EqualStrings([]string{"subnet-private"}, internal) {
+				t.Errorf("expected public subnets [subnet-public] and internal subnets [subnet-private], got %v and %v", public, internal)
+			}
+			if !utils.EqualStrings([]string{"subnet-private", "subnet-public"}, tagged) || len(untagged) != 0 {
+				t.Errorf("expected tagged subnets [subnet-private subnet-public] and no untagged subnets, got %v and %v", tagged, untagged)
+			}
+			for id, expected := range tc.expectedTags {
+				if diff := cmp.Diff(expected, ec2Client.Tags(id)); diff != "" {
+					t.Errorf("unexpected tags of %s (-want +got):\n%s", id, diff)
+				}
+			}
+
+			// switching to the manual policy removes the tags added by the operator
+			controller.Spec.SubnetTagging = albo.ManualSubnetTaggingPolicy
+			ec2Client.SetTagPropagationDelay(0)
+			for i := 0; i < 10 && len(ec2Client.Tags("subnet-public")) > 1; i++ {
+				if _, _, _, _, err := r.tagSubnets(context.Background(), controller); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			for _, id := range []string{"subnet-public", "subnet-private"} {
+				if diff := cmp.Diff(map[string]string{"kubernetes.io/cluster/test-cluster": "shared"}, ec2Client.Tags(id)); diff != "" {
+					t.Errorf("unexpected tags of %s after switching to manual tagging (-want +got):\n%s", id, diff)
+				}
+			}
+		})
+	}
+}
+
 type testEC2Client struct {
-	t                 *testing.T
-	subnets           []ec2types.Subnet
-	clusterID         string
-	taggedResources   []string
-	untaggedResources []string
+	t                         *testing.T
+	subnets                   []ec2types.Subnet
+	routeTables               []ec2types.RouteTable
+	clusterID                 string
+	taggedResources           []string
+	untaggedResources         []string
+	taggedInternalResources   []string
+	untaggedInternalResources []string
 	aws.VPCClient
 }
 
@@ -252,6 +619,12 @@ var badQueryError = errors.New("bad query")
 
 func (t *testEC2Client) DescribeSubnets(_ context.Context, input *ec2.DescribeSubnetsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
 	t.t.Helper()
+	if len(input.SubnetIds) > 0 {
+		return &ec2.DescribeSubnetsOutput{Subnets: t.subnetsByID(input.SubnetIds)}, nil
+	}
+	if len(input.Filters) > 0 && awstypes.ToString(input.Filters[0].Name) == vpcIDFilterName {
+		return &ec2.DescribeSubnetsOutput{Subnets: t.subnetsByFilters(input.Filters)}, nil
+	}
 	if len(input.Filters) != 1 {
 		t.t.Errorf("query does not have correct number of filters")
 		return nil, badQueryError
@@ -271,21 +644,81 @@ func (t *testEC2Client) DescribeSubnets(_ context.Context, input *ec2.DescribeSu
 	return &ec2.DescribeSubnetsOutput{Subnets: t.subnets}, nil
 }
 
+// subnetsByID returns the subnets with the given IDs, the unknown IDs are ignored.
+func (t *testEC2Client) subnetsByID(subnetIDs []string) []ec2types.Subnet {
+	ids := sets.New[string](subnetIDs...)
+	var subnets []ec2types.Subnet
+	for _, s := range t.subnets {
+		if ids.Has(awstypes.ToString(s.SubnetId)) {
+			subnets = append(subnets, s)
+		}
+	}
+	return subnets
+}
+
+// subnetsByFilters returns the subnets matching the vpc-id and tag:<key> filters.
+func (t *testEC2Client) subnetsByFilters(filters []ec2types.Filter) []ec2types.Subnet {
+	var subnets []ec2types.Subnet
+	for _, s := range t.subnets {
+		matches := true
+		for _, f := range filters {
+			name := awstypes.ToString(f.Name)
+			switch {
+			case name == vpcIDFilterName:
+				matches = matches && f.Values[0] == awstypes.ToString(s.VpcId)
+			case strings.HasPrefix(name, "tag:"):
+				key := strings.TrimPrefix(name, "tag:")
+				matched := false
+				for _, tag := range s.Tags {
+					if awstypes.ToString(tag.Key) == key && (f.Values[0] == anyTagValue || f.Values[0] == awstypes.ToString(tag.Value)) {
+						matched = true
+					}
+				}
+				matches = matches && matched
+			default:
+				t.t.Errorf("unexpected filter name %s", name)
+				return nil
+			}
+		}
+		if matches {
+			subnets = append(subnets, s)
+		}
+	}
+	return subnets
+}
+
+func (t *testEC2Client) DescribeRouteTables(_ context.Context, input *ec2.DescribeRouteTablesInput, _ ...func(*ec2.Options)) (*ec2.DescribeRouteTablesOutput, error) {
+	t.t.Helper()
+	if len(input.Filters) != 1 {
+		t.t.Errorf("query does not have correct number of filters")
+		return nil, badQueryError
+	}
+	if awstypes.ToString(input.Filters[0].Name) != vpcIDFilterName {
+		t.t.Errorf("unexpected filter name %s", awstypes.ToString(input.Filters[0].Name))
+		return nil, badQueryError
+	}
+	return &ec2.DescribeRouteTablesOutput{RouteTables: t.routeTables}, nil
+}
+
 func (t *testEC2Client) CreateTags(_ context.Context, input *ec2.CreateTagsInput, _ ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
 	t.t.Helper()
 	if len(input.Tags) != 2 {
 		t.t.Errorf("unexpected number of tags: %d", len(input.Tags))
 		return nil, badQueryError
 	}
-	if !hasTag(input.Tags, publicELBTagKey) {
-		t.t.Errorf("input %v does not have tag key %s", input.Tags, publicELBTagKey)
-		return nil, badQueryError
-	}
 	if !hasTag(input.Tags, tagKeyALBOTagged) {
 		t.t.Errorf("input %v does not have tag key %s", input.Tags, tagKeyALBOTagged)
 		return nil, badQueryError
 	}
-	t.taggedResources = append(t.taggedResources, input.Resources...)
+	switch {
+	case hasTag(input.Tags, publicELBTagKey):
+		t.taggedResources = append(t.taggedResources, input.Resources...)
+	case hasTag(input.Tags, internalELBTagKey):
+		t.taggedInternalResources = append(t.taggedInternalResources, input.Resources...)
+	default:
+		t.t.Errorf("input %v does not have any of the tag keys %s, %s", input.Tags, publicELBTagKey, internalELBTagKey)
+		return nil, badQueryError
+	}
 	return nil, nil
 }
 
@@ -295,14 +728,18 @@ func (t *testEC2Client) DeleteTags(ctx context.Context, input *ec2.DeleteTagsInp
 		t.t.Errorf("unexpected number of tags: %d", len(input.Tags))
 		return nil, badQueryError
 	}
-	if !hasTag(input.Tags, publicELBTagKey) {
-		t.t.Errorf("input %v does not have tag key %s", input.Tags, publicELBTagKey)
-		return nil, badQueryError
-	}
 	if !hasTag(input.Tags, tagKeyALBOTagged) {
 		t.t.Errorf("input %v does not have tag key %s", input.Tags, tagKeyALBOTagged)
 		return nil, badQueryError
 	}
-	t.untaggedResources = append(t.untaggedResources, input.Resources...)
+	switch {
+	case hasTag(input.Tags, publicELBTagKey):
+		t.untaggedResources = append(t.untaggedResources, input.Resources...)
+	case hasTag(input.Tags, internalELBTagKey):
+		t.untaggedInternalResources = append(t.untaggedInternalResources, input.Resources...)
+	default:
+		t.t.Errorf("input %v does not have any of the tag keys %s, %s", input.Tags, publicELBTagKey, internalELBTagKey)
+		return nil, badQueryError
+	}
 	return nil, nil
 }
@@ -23,16 +23,38 @@ import (
 const (
 	injectCABundleAnnotationKey   = "service.beta.openshift.io/inject-cabundle"
 	injectCABundleAnnotationValue = "true"
+	// controllerInstanceLabelKey is the label which assigns TargetGroupBindings to an instance of the controller.
+	// The TargetGroupBindings without this label, like the ones created by the controllers,
+	// are served by the default instance or by the oldest instance if there's no default instance.
+	controllerInstanceLabelKey = "networking.olm.openshift.io/aws-load-balancer-controller"
+	// podReadinessGateInjectLabelKey is the label which enables the injection of the pod readiness gates in a namespace.
+	podReadinessGateInjectLabelKey   = "elbv2.k8s.aws/pod-readiness-gate-inject"
+	podReadinessGateInjectLabelValue = "enabled"
+	// defaultWebhookTimeoutSeconds is the timeout of the webhook calls when none is specified.
+	// It matches the default of the API server.
+	defaultWebhookTimeoutSeconds = 10
+	// runLevelLabelKey is the label set on the namespaces of the OpenShift control plane and core operators.
+	runLevelLabelKey = "openshift.io/run-level"
 )
 
+// platformNamespaces are the namespaces excluded from the Ingress, Pod and Service webhooks by default
+// along with the namespaces labeled for the cluster monitoring or with a run level.
+// The other openshift-* namespaces, like the ones of the optional operators, are still sent to the webhooks.
+var platformNamespaces = []string{"default", "kube-node-lease", "kube-public", "kube-system"}
+
 // ensureWebhooks ensures that the ValidatingWebhookConfiguration and MutatingWebhookConfiguration resources associated with the controller
 // are created and up-to-date.
 func (r *AWSLoadBalancerControllerReconciler) ensureWebhooks(ctx context.Context, controller *albo.AWSLoadBalancerController, service *corev1.Service) error {
 	reqLogger := log.FromContext(ctx).WithValues("webhook", controller.Name)
 	reqLogger.Info("ensuring validating and mutating webhook configurations for aws-load-balancer-controller instance")
 
-	desiredVWC := desiredValidatingWebhookConfiguration(controller, service)
-	err := controllerutil.SetControllerReference(controller, desiredVWC, r.Scheme)
+	tgbSelector, err := r.targetGroupBindingObjectSelector(ctx, controller)
+	if err != nil {
+		return fmt.Errorf("failed to build the TargetGroupBinding selector of the webhooks: %w", err)
+	}
+
+	desiredVWC := desiredValidatingWebhookConfiguration(controller, service, tgbSelector)
+	err = controllerutil.SetControllerReference(controller, desiredVWC, r.Scheme)
 	if err != nil {
 		return fmt.Errorf("failed to set owner reference on desired ValidatingWebhookConfiguration %q: %w", desiredVWC.Name, err)
 	}
@@ -55,7 +77,7 @@ func (r *AWSLoadBalancerControllerReconciler) ensureWebhooks(ctx context.Context
 		}
 	}
 
-	desiredMWC := desiredMutatingWebhookConfiguration(controller, service)
+	desiredMWC := desiredMutatingWebhookConfiguration(controller, service, tgbSelector)
 	err = controllerutil.SetControllerReference(controller, desiredMWC, r.Scheme)
 	if err != nil {
 		return fmt.Errorf("failed to set owner reference on desired MutatingWebhookConfiguration %q: %w", desiredMWC.Name, err)
@@ -100,7 +122,8 @@ func (r *AWSLoadBalancerControllerReconciler) currentMutatingWebhookConfiguratio
 	return &currentMWC, true, err
 }
 
-func desiredValidatingWebhookConfiguration(controller *albo.AWSLoadBalancerController, webhookService *corev1.Service) *arv1.ValidatingWebhookConfiguration {
+func desiredValidatingWebhookConfiguration(controller *albo.AWSLoadBalancerController, webhookService *corev1.Service, tgbSelector *metav1.LabelSelector) *arv1.ValidatingWebhookConfiguration {
+	webhooks := webhooksConfig(controller)
 	return &arv1.ValidatingWebhookConfiguration{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: fmt.Sprintf("%s-%s", controllerResourcePrefix, controller.Name),
@@ -133,12 +156,17 @@ func desiredValidatingWebhookConfiguration(controller *albo.AWSLoadBalancerContr
 						},
 					},
 				},
-				FailurePolicy:           failurePolicyPtr(arv1.Fail),
+				NamespaceSelector:       webhookNamespaceSelector(webhooks.TargetGroupBinding, &metav1.LabelSelector{}),
+				ObjectSelector:          combineSelectors(tgbSelector, webhookObjectSelector(webhooks.TargetGroupBinding)),
+				FailurePolicy:           webhookFailurePolicy(webhooks.TargetGroupBinding),
+				TimeoutSeconds:          webhookTimeoutSeconds(webhooks.TargetGroupBinding),
 				MatchPolicy:             matchPolicyPtr(arv1.Equivalent),
 				SideEffects:             sideEffectPtr(arv1.SideEffectClassNone),
 				AdmissionReviewVersions: []string{"v1beta1"},
 			},
 			{
+				// the ingress webhook is not scoped as the controller's validator
+				// skips the ingresses which don't belong to its ingress class
 				Name: "vingress.elbv2.k8s.aws",
 				ClientConfig: arv1.WebhookClientConfig{
 					Service: &arv1.ServiceReference{
@@ -162,7 +190,10 @@ func desiredValidatingWebhookConfiguration(controller *albo.AWSLoadBalancerContr
 						},
 					},
 				},
-				FailurePolicy:           failurePolicyPtr(arv1.Fail),
+				NamespaceSelector:       webhookNamespaceSelector(webhooks.Ingress, platformNamespacesExcludedSelector()),
+				ObjectSelector:          combineSelectors(webhookObjectSelector(webhooks.Ingress)),
+				FailurePolicy:           webhookFailurePolicy(webhooks.Ingress),
+				TimeoutSeconds:          webhookTimeoutSeconds(webhooks.Ingress),
 				MatchPolicy:             matchPolicyPtr(arv1.Equivalent),
 				SideEffects:             sideEffectPtr(arv1.SideEffectClassNone),
 				AdmissionReviewVersions: []string{"v1beta1"},
@@ -171,6 +202,164 @@ func desiredValidatingWebhookConfiguration(controller *albo.AWSLoadBalancerContr
 	}
 }
 
+// targetGroupBindingObjectSelector returns the selector of the TargetGroupBindings handled by the webhooks of the given instance.
+// The TargetGroupBindings created by the controllers don't carry the instance label, they are handled
+// along with the TargetGroupBindings of the users without the label by a single instance:
+// the default instance if it exists, the oldest instance otherwise. The selectors of the instances don't overlap:
+// this instance handles all the TargetGroupBindings which are not labelled with the name of another instance,
+// any other instance handles the TargetGroupBindings labelled with its name.
+func (r *AWSLoadBalancerControllerReconciler) targetGroupBindingObjectSelector(ctx context.Context, controller *albo.AWSLoadBalancerController) (*metav1.LabelSelector, error) {
+	var controllers albo.AWSLoadBalancerControllerList
+	if err := r.List(ctx, &controllers); err != nil {
+		return nil, err
+	}
+	instances := []*albo.AWSLoadBalancerController{controller}
+	var others []string
+	for i := range controllers.Items {
+		other := &controllers.Items[i]
+		if other.Name == controller.Name || other.DeletionTimestamp != nil {
+			continue
+		}
+		instances = append(instances, other)
+		others = append(others, other.Name)
+	}
+
+	if unlabelledTargetGroupBindingsHandler(instances) != controller.Name {
+		return &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				controllerInstanceLabelKey: controller.Name,
+			},
+		}, nil
+	}
+	if len(others) == 0 {
+		return &metav1.LabelSelector{}, nil
+	}
+	sort.Strings(others)
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      controllerInstanceLabelKey,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   others,
+			},
+		},
+	}, nil
+}
+
+// unlabelledTargetGroupBindingsHandler returns the name of the instance which handles the TargetGroupBindings
+// without the instance label: the default instance if it's one of the given instances, the oldest instance otherwise.
+// The name is used as a tie-breaker.
+func unlabelledTargetGroupBindingsHandler(instances []*albo.AWSLoadBalancerController) string {
+	var handler *albo.AWSLoadBalancerController
+	for _, instance := range instances {
+		if instance.Name == controllerName {
+			return instance.Name
+		}
+		if handler == nil || instance.CreationTimestamp.Before(&handler.CreationTimestamp) ||
+			(instance.CreationTimestamp.Equal(&handler.CreationTimestamp) && instance.Name < handler.Name) {
+			handler = instance
+		}
+	}
+	if handler == nil {
+		return ""
+	}
+	return handler.Name
+}
+
+// webhooksConfig returns the customization of the webhooks of the given instance.
+func webhooksConfig(controller *albo.AWSLoadBalancerController) albo.AWSLoadBalancerControllerWebhooks {
+	if controller.Spec.Webhooks == nil {
+		return albo.AWSLoadBalancerControllerWebhooks{}
+	}
+	return *controller.Spec.Webhooks
+}
+
+// webhookFailurePolicy returns the failure policy of the webhook, the webhooks fail closed by default.
+func webhookFailurePolicy(config *albo.AWSLoadBalancerWebhookConfig) *arv1.FailurePolicyType {
+	if config != nil && config.FailurePolicy == albo.WebhookFailurePolicyIgnore {
+		return failurePolicyPtr(arv1.Ignore)
+	}
+	return failurePolicyPtr(arv1.Fail)
+}
+
+// webhookTimeoutSeconds returns the timeout of the webhook calls.
+func webhookTimeoutSeconds(config *albo.AWSLoadBalancerWebhookConfig) *int32 {
+	if config != nil && config.TimeoutSeconds > 0 {
+		return ptr.To[int32](config.TimeoutSeconds)
+	}
+	return ptr.To[int32](defaultWebhookTimeoutSeconds)
+}
+
+// webhookNamespaceSelector returns the namespace selector of the webhook or the given default selector if none is specified.
+func webhookNamespaceSelector(config *albo.AWSLoadBalancerWebhookConfig, defaultSelector *metav1.LabelSelector) *metav1.LabelSelector {
+	if config != nil && config.NamespaceSelector != nil {
+		return config.NamespaceSelector.DeepCopy()
+	}
+	return defaultSelector
+}
+
+// webhookObjectSelector returns the object selector of the webhook, nil if none is specified.
+func webhookObjectSelector(config *albo.AWSLoadBalancerWebhookConfig) *metav1.LabelSelector {
+	if config == nil {
+		return nil
+	}
+	return config.ObjectSelector
+}
+
+// platformNamespacesExcludedSelector returns the namespace selector which excludes the platform namespaces.
+func platformNamespacesExcludedSelector() *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      namespaceNameLabelKey,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   platformNamespaces,
+			},
+			{
+				Key:      clusterMonitoringLabelKey,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{"true"},
+			},
+			{
+				// the run level can be empty, any value excludes the namespace
+				Key:      runLevelLabelKey,
+				Operator: metav1.LabelSelectorOpDoesNotExist,
+			},
+		},
+	}
+}
+
+// combineSelectors returns the selector which matches the objects matched by all the given selectors.
+// The labels of all but the first selector are turned into expressions so that the same label
+// required with different values by several selectors doesn't match any object.
+// An empty selector which matches all the objects is returned if no selector is given.
+func combineSelectors(selectors ...*metav1.LabelSelector) *metav1.LabelSelector {
+	combined := &metav1.LabelSelector{}
+	for i, selector := range selectors {
+		if selector == nil {
+			continue
+		}
+		if i == 0 || len(combined.MatchLabels) == 0 && len(combined.MatchExpressions) == 0 {
+			combined = selector.DeepCopy()
+			continue
+		}
+		keys := make([]string, 0, len(selector.MatchLabels))
+		for key := range selector.MatchLabels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			combined.MatchExpressions = append(combined.MatchExpressions, metav1.LabelSelectorRequirement{
+				Key:      key,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{selector.MatchLabels[key]},
+			})
+		}
+		combined.MatchExpressions = append(combined.MatchExpressions, selector.MatchExpressions...)
+	}
+	return combined
+}
+
 func sideEffectPtr(sideEffectClass arv1.SideEffectClass) *arv1.SideEffectClass {
 	return &sideEffectClass
 }
@@ -271,6 +460,15 @@ func haveValidatingWebhooksChanged(updated sortableValidatingWebhooks, desired s
 		if !equality.Semantic.DeepEqual(u.Rules, d.Rules) {
 			return true
 		}
+		if d.ObjectSelector != nil && !equality.Semantic.DeepEqual(u.ObjectSelector, d.ObjectSelector) {
+			return true
+		}
+		if d.NamespaceSelector != nil && !equality.Semantic.DeepEqual(u.NamespaceSelector, d.NamespaceSelector) {
+			return true
+		}
+		if d.TimeoutSeconds != nil && !equality.Semantic.DeepEqual(u.TimeoutSeconds, d.TimeoutSeconds) {
+			return true
+		}
 		if d.FailurePolicy != nil {
 			if u.FailurePolicy == nil {
 				return true
@@ -321,8 +519,9 @@ func rulesEqual(desired arv1.RuleWithOperations, current arv1.RuleWithOperations
 	return true
 }
 
-func desiredMutatingWebhookConfiguration(controller *albo.AWSLoadBalancerController, webhookService *corev1.Service) *arv1.MutatingWebhookConfiguration {
-	return &arv1.MutatingWebhookConfiguration{
+func desiredMutatingWebhookConfiguration(controller *albo.AWSLoadBalancerController, webhookService *corev1.Service, tgbSelector *metav1.LabelSelector) *arv1.MutatingWebhookConfiguration {
+	webhooks := webhooksConfig(controller)
+	mwc := &arv1.MutatingWebhookConfiguration{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: fmt.Sprintf("%s-%s", controllerResourcePrefix, controller.Name),
 			Annotations: map[string]string{
@@ -339,8 +538,11 @@ func desiredMutatingWebhookConfiguration(controller *albo.AWSLoadBalancerControl
 						Port:      ptr.To[int32](controllerWebhookPort),
 					},
 				},
-				FailurePolicy: failurePolicyPtr(arv1.Fail),
-				Name:          "mtargetgroupbinding.elbv2.k8s.aws",
+				FailurePolicy:     webhookFailurePolicy(webhooks.TargetGroupBinding),
+				TimeoutSeconds:    webhookTimeoutSeconds(webhooks.TargetGroupBinding),
+				Name:              "mtargetgroupbinding.elbv2.k8s.aws",
+				NamespaceSelector: webhookNamespaceSelector(webhooks.TargetGroupBinding, &metav1.LabelSelector{}),
+				ObjectSelector:    combineSelectors(tgbSelector, webhookObjectSelector(webhooks.TargetGroupBinding)),
 				Rules: []arv1.RuleWithOperations{
 					{
 						Rule: arv1.Rule{
@@ -359,6 +561,103 @@ func desiredMutatingWebhookConfiguration(controller *albo.AWSLoadBalancerControl
 			},
 		},
 	}
+	if controller.Spec.PodReadinessGateInjection == albo.PodReadinessGateInjectionEnabled {
+		mwc.Webhooks = append(mwc.Webhooks, desiredPodMutatingWebhook(webhookService, webhooks.Pod))
+	}
+	if controller.Spec.DefaultLoadBalancerClass == albo.DefaultLoadBalancerClassEnabled {
+		mwc.Webhooks = append(mwc.Webhooks, desiredServiceMutatingWebhook(webhookService, webhooks.Service))
+	}
+	return mwc
+}
+
+// desiredServiceMutatingWebhook returns the webhook which assigns the controller's load balancer class
+// to the Services of type LoadBalancer created without a load balancer class.
+func desiredServiceMutatingWebhook(webhookService *corev1.Service, config *albo.AWSLoadBalancerWebhookConfig) arv1.MutatingWebhook {
+	return arv1.MutatingWebhook{
+		AdmissionReviewVersions: []string{"v1beta1"},
+		ClientConfig: arv1.WebhookClientConfig{
+			Service: &arv1.ServiceReference{Name: webhookService.Name,
+				Namespace: webhookService.Namespace,
+				Path:      ptr.To[string]("/mutate-v1-service"),
+				Port:      ptr.To[int32](controllerWebhookPort),
+			},
+		},
+		FailurePolicy:     webhookFailurePolicy(config),
+		TimeoutSeconds:    webhookTimeoutSeconds(config),
+		Name:              "mservice.elbv2.k8s.aws",
+		NamespaceSelector: webhookNamespaceSelector(config, platformNamespacesExcludedSelector()),
+		ObjectSelector:    combineSelectors(operandExcludedObjectSelector(), webhookObjectSelector(config)),
+		Rules: []arv1.RuleWithOperations{
+			{
+				Rule: arv1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"services"},
+					Scope:       scopeTypePtr(arv1.NamespacedScope),
+				},
+				Operations: []arv1.OperationType{
+					arv1.Create,
+				},
+			},
+		},
+		SideEffects: sideEffectPtr(arv1.SideEffectClassNone),
+	}
+}
+
+// operandExcludedObjectSelector returns the selector which excludes the resources of the controller from the webhooks
+// so that they can be created even if no controller is available to serve the webhooks.
+func operandExcludedObjectSelector() *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      appLabelName,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{appName},
+			},
+		},
+	}
+}
+
+// desiredPodMutatingWebhook returns the webhook which injects the readiness gates into the pods
+// created in the namespaces labeled for the injection.
+func desiredPodMutatingWebhook(webhookService *corev1.Service, config *albo.AWSLoadBalancerWebhookConfig) arv1.MutatingWebhook {
+	return arv1.MutatingWebhook{
+		AdmissionReviewVersions: []string{"v1beta1"},
+		ClientConfig: arv1.WebhookClientConfig{
+			Service: &arv1.ServiceReference{Name: webhookService.Name,
+				Namespace: webhookService.Namespace,
+				Path:      ptr.To[string]("/mutate-v1-pod"),
+				Port:      ptr.To[int32](controllerWebhookPort),
+			},
+		},
+		FailurePolicy:  webhookFailurePolicy(config),
+		TimeoutSeconds: webhookTimeoutSeconds(config),
+		Name:           "mpod.elbv2.k8s.aws",
+		NamespaceSelector: combineSelectors(&metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      podReadinessGateInjectLabelKey,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   []string{podReadinessGateInjectLabelValue},
+				},
+			},
+		}, webhookNamespaceSelector(config, platformNamespacesExcludedSelector())),
+		ObjectSelector: combineSelectors(operandExcludedObjectSelector(), webhookObjectSelector(config)),
+		Rules: []arv1.RuleWithOperations{
+			{
+				Rule: arv1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"pods"},
+					Scope:       scopeTypePtr(arv1.NamespacedScope),
+				},
+				Operations: []arv1.OperationType{
+					arv1.Create,
+				},
+			},
+		},
+		SideEffects: sideEffectPtr(arv1.SideEffectClassNone),
+	}
 }
 
 func (r *AWSLoadBalancerControllerReconciler) updateMutatingWebhookConfiguration(ctx context.Context, current, desired *arv1.MutatingWebhookConfiguration) error {
@@ -417,6 +716,15 @@ func haveMutatingWebhooksChanged(updated, desired sortableMutatingWebhooks) bool
 		if !equality.Semantic.DeepEqual(u.Rules, d.Rules) {
 			return true
 		}
+		if d.ObjectSelector != nil && !equality.Semantic.DeepEqual(u.ObjectSelector, d.ObjectSelector) {
+			return true
+		}
+		if d.NamespaceSelector != nil && !equality.Semantic.DeepEqual(u.NamespaceSelector, d.NamespaceSelector) {
+			return true
+		}
+		if d.TimeoutSeconds != nil && !equality.Semantic.DeepEqual(u.TimeoutSeconds, d.TimeoutSeconds) {
+			return true
+		}
 		if d.FailurePolicy != nil {
 			if u.FailurePolicy == nil {
 				return true
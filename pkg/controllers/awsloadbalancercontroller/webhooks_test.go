@@ -3,11 +3,13 @@ package awsloadbalancercontroller
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	arv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -147,6 +149,45 @@ func TestAreValidatingWebhooksSame(t *testing.T) {
 			desiredVWs:     []arv1.ValidatingWebhook{{Name: "a", FailurePolicy: failurePolicyPtr(arv1.Fail)}},
 			expectedResult: true,
 		},
+		{
+			name:       "desired object selector is nil",
+			currentVWs: []arv1.ValidatingWebhook{{Name: "a", ObjectSelector: &metav1.LabelSelector{}}},
+			desiredVWs: []arv1.ValidatingWebhook{{Name: "a"}},
+		},
+		{
+			name: "current and desired object selector differ",
+			currentVWs: []arv1.ValidatingWebhook{{Name: "a", ObjectSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"networking.olm.openshift.io/aws-load-balancer-controller": "internal"},
+			}}},
+			desiredVWs: []arv1.ValidatingWebhook{{Name: "a", ObjectSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"networking.olm.openshift.io/aws-load-balancer-controller": "external"},
+			}}},
+			expectedResult: true,
+		},
+		{
+			name:       "desired namespace selector is nil",
+			currentVWs: []arv1.ValidatingWebhook{{Name: "a", NamespaceSelector: &metav1.LabelSelector{}}},
+			desiredVWs: []arv1.ValidatingWebhook{{Name: "a"}},
+		},
+		{
+			name:       "current and desired namespace selectors differ",
+			currentVWs: []arv1.ValidatingWebhook{{Name: "a", NamespaceSelector: &metav1.LabelSelector{}}},
+			desiredVWs: []arv1.ValidatingWebhook{{Name: "a", NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"example.org/team": "web"},
+			}}},
+			expectedResult: true,
+		},
+		{
+			name:       "desired timeout is nil",
+			currentVWs: []arv1.ValidatingWebhook{{Name: "a", TimeoutSeconds: ptr.To[int32](10)}},
+			desiredVWs: []arv1.ValidatingWebhook{{Name: "a"}},
+		},
+		{
+			name:           "current and desired timeouts differ",
+			currentVWs:     []arv1.ValidatingWebhook{{Name: "a", TimeoutSeconds: ptr.To[int32](10)}},
+			desiredVWs:     []arv1.ValidatingWebhook{{Name: "a", TimeoutSeconds: ptr.To[int32](5)}},
+			expectedResult: true,
+		},
 		{
 			name:       "rules have changed",
 			currentVWs: []arv1.ValidatingWebhook{{Name: "a", Rules: []arv1.RuleWithOperations{}}},
@@ -294,6 +335,19 @@ func TestAreMutatingWebhooksSame(t *testing.T) {
 			desiredVWs:     []arv1.MutatingWebhook{{Name: "a", SideEffects: sideEffectPtr(arv1.SideEffectClassNone)}},
 			expectedResult: true,
 		},
+		{
+			name:       "desired namespace selector is nil",
+			currentVWs: []arv1.MutatingWebhook{{Name: "a", NamespaceSelector: &metav1.LabelSelector{}}},
+			desiredVWs: []arv1.MutatingWebhook{{Name: "a"}},
+		},
+		{
+			name:       "current and desired namespace selectors differ",
+			currentVWs: []arv1.MutatingWebhook{{Name: "a", NamespaceSelector: &metav1.LabelSelector{}}},
+			desiredVWs: []arv1.MutatingWebhook{{Name: "a", NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"elbv2.k8s.aws/pod-readiness-gate-inject": "enabled"},
+			}}},
+			expectedResult: true,
+		},
 		{
 			name:       "desired match policy is nil",
 			currentVWs: []arv1.MutatingWebhook{{Name: "a", MatchPolicy: matchPolicyPtr(arv1.Equivalent)}},
@@ -328,6 +382,32 @@ func TestAreMutatingWebhooksSame(t *testing.T) {
 			desiredVWs:     []arv1.MutatingWebhook{{Name: "a", FailurePolicy: failurePolicyPtr(arv1.Fail)}},
 			expectedResult: true,
 		},
+		{
+			name:       "desired object selector is nil",
+			currentVWs: []arv1.MutatingWebhook{{Name: "a", ObjectSelector: &metav1.LabelSelector{}}},
+			desiredVWs: []arv1.MutatingWebhook{{Name: "a"}},
+		},
+		{
+			name: "current and desired object selector differ",
+			currentVWs: []arv1.MutatingWebhook{{Name: "a", ObjectSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"networking.olm.openshift.io/aws-load-balancer-controller": "internal"},
+			}}},
+			desiredVWs: []arv1.MutatingWebhook{{Name: "a", ObjectSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"networking.olm.openshift.io/aws-load-balancer-controller": "external"},
+			}}},
+			expectedResult: true,
+		},
+		{
+			name:       "desired timeout is nil",
+			currentVWs: []arv1.MutatingWebhook{{Name: "a", TimeoutSeconds: ptr.To[int32](10)}},
+			desiredVWs: []arv1.MutatingWebhook{{Name: "a"}},
+		},
+		{
+			name:           "current and desired timeouts differ",
+			currentVWs:     []arv1.MutatingWebhook{{Name: "a", TimeoutSeconds: ptr.To[int32](10)}},
+			desiredVWs:     []arv1.MutatingWebhook{{Name: "a", TimeoutSeconds: ptr.To[int32](5)}},
+			expectedResult: true,
+		},
 		{
 			name:       "rules have changed",
 			currentVWs: []arv1.MutatingWebhook{{Name: "a", Rules: []arv1.RuleWithOperations{}}},
@@ -379,7 +459,7 @@ func TestAreMutatingWebhooksSame(t *testing.T) {
 	}
 }
 
-func testValidatingWebhooks(serviceName, serviceNamespace string) []arv1.ValidatingWebhook {
+func testValidatingWebhooks(serviceName, serviceNamespace string, objectSelector *metav1.LabelSelector) []arv1.ValidatingWebhook {
 	return []arv1.ValidatingWebhook{
 		{
 			Name: "vtargetgroupbinding.elbv2.k8s.aws",
@@ -405,7 +485,10 @@ func testValidatingWebhooks(serviceName, serviceNamespace string) []arv1.Validat
 					},
 				},
 			},
+			NamespaceSelector:       &metav1.LabelSelector{},
+			ObjectSelector:          objectSelector,
 			FailurePolicy:           failurePolicyPtr(arv1.Fail),
+			TimeoutSeconds:          ptr.To[int32](10),
 			MatchPolicy:             matchPolicyPtr(arv1.Equivalent),
 			SideEffects:             sideEffectPtr(arv1.SideEffectClassNone),
 			AdmissionReviewVersions: []string{"v1beta1"},
@@ -434,7 +517,10 @@ func testValidatingWebhooks(serviceName, serviceNamespace string) []arv1.Validat
 					},
 				},
 			},
+			NamespaceSelector:       testPlatformNamespacesExcludedSelector(),
+			ObjectSelector:          &metav1.LabelSelector{},
 			FailurePolicy:           failurePolicyPtr(arv1.Fail),
+			TimeoutSeconds:          ptr.To[int32](10),
 			MatchPolicy:             matchPolicyPtr(arv1.Equivalent),
 			SideEffects:             sideEffectPtr(arv1.SideEffectClassNone),
 			AdmissionReviewVersions: []string{"v1beta1"},
@@ -442,7 +528,7 @@ func testValidatingWebhooks(serviceName, serviceNamespace string) []arv1.Validat
 	}
 }
 
-func testMutatingWebhooks(serviceName, serviceNamespace string) []arv1.MutatingWebhook {
+func testMutatingWebhooks(serviceName, serviceNamespace string, objectSelector *metav1.LabelSelector) []arv1.MutatingWebhook {
 	return []arv1.MutatingWebhook{
 		{
 			AdmissionReviewVersions: []string{"v1beta1"},
@@ -453,8 +539,11 @@ func testMutatingWebhooks(serviceName, serviceNamespace string) []arv1.MutatingW
 					Port:      ptr.To[int32](controllerWebhookPort),
 				},
 			},
-			FailurePolicy: failurePolicyPtr(arv1.Fail),
-			Name:          "mtargetgroupbinding.elbv2.k8s.aws",
+			FailurePolicy:     failurePolicyPtr(arv1.Fail),
+			TimeoutSeconds:    ptr.To[int32](10),
+			Name:              "mtargetgroupbinding.elbv2.k8s.aws",
+			NamespaceSelector: &metav1.LabelSelector{},
+			ObjectSelector:    objectSelector,
 			Rules: []arv1.RuleWithOperations{
 				{
 					Rule: arv1.Rule{
@@ -474,6 +563,197 @@ func testMutatingWebhooks(serviceName, serviceNamespace string) []arv1.MutatingW
 	}
 }
 
+func testPodMutatingWebhook(serviceName, serviceNamespace string) arv1.MutatingWebhook {
+	return arv1.MutatingWebhook{
+		AdmissionReviewVersions: []string{"v1beta1"},
+		ClientConfig: arv1.WebhookClientConfig{
+			Service: &arv1.ServiceReference{Name: serviceName,
+				Namespace: serviceNamespace,
+				Path:      ptr.To[string]("/mutate-v1-pod"),
+				Port:      ptr.To[int32](controllerWebhookPort),
+			},
+		},
+		FailurePolicy:  failurePolicyPtr(arv1.Fail),
+		TimeoutSeconds: ptr.To[int32](10),
+		Name:           "mpod.elbv2.k8s.aws",
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchExpressions: append([]metav1.LabelSelectorRequirement{
+				{
+					Key:      "elbv2.k8s.aws/pod-readiness-gate-inject",
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   []string{"enabled"},
+				},
+			}, testPlatformNamespacesExcludedSelector().MatchExpressions...),
+		},
+		ObjectSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      "app.kubernetes.io/name",
+					Operator: metav1.LabelSelectorOpNotIn,
+					Values:   []string{"aws-load-balancer-operator"},
+				},
+			},
+		},
+		Rules: []arv1.RuleWithOperations{
+			{
+				Rule: arv1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"pods"},
+					Scope:       scopeTypePtr(arv1.NamespacedScope),
+				},
+				Operations: []arv1.OperationType{
+					arv1.Create,
+				},
+			},
+		},
+		SideEffects: sideEffectPtr(arv1.SideEffectClassNone),
+	}
+}
+
+func testServiceMutatingWebhook(serviceName, serviceNamespace string) arv1.MutatingWebhook {
+	return arv1.MutatingWebhook{
+		AdmissionReviewVersions: []string{"v1beta1"},
+		ClientConfig: arv1.WebhookClientConfig{
+			Service: &arv1.ServiceReference{Name: serviceName,
+				Namespace: serviceNamespace,
+				Path:      ptr.To[string]("/mutate-v1-service"),
+				Port:      ptr.To[int32](controllerWebhookPort),
+			},
+		},
+		FailurePolicy:     failurePolicyPtr(arv1.Fail),
+		TimeoutSeconds:    ptr.To[int32](10),
+		Name:              "mservice.elbv2.k8s.aws",
+		NamespaceSelector: testPlatformNamespacesExcludedSelector(),
+		ObjectSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      "app.kubernetes.io/name",
+					Operator: metav1.LabelSelectorOpNotIn,
+					Values:   []string{"aws-load-balancer-operator"},
+				},
+			},
+		},
+		Rules: []arv1.RuleWithOperations{
+			{
+				Rule: arv1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"services"},
+					Scope:       scopeTypePtr(arv1.NamespacedScope),
+				},
+				Operations: []arv1.OperationType{
+					arv1.Create,
+				},
+			},
+		},
+		SideEffects: sideEffectPtr(arv1.SideEffectClassNone),
+	}
+}
+
+// testDefaultInstanceObjectSelector returns the selector of the TargetGroupBindings of the default instance
+// when it's the only instance: all the TargetGroupBindings are handled by the default instance.
+func testDefaultInstanceObjectSelector() *metav1.LabelSelector {
+	return &metav1.LabelSelector{}
+}
+
+// testOtherInstancesExcludedObjectSelector returns the selector of the TargetGroupBindings of the instance
+// which handles the TargetGroupBindings without the instance label when the given instances exist too.
+func testOtherInstancesExcludedObjectSelector(others ...string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      "networking.olm.openshift.io/aws-load-balancer-controller",
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   others,
+			},
+		},
+	}
+}
+
+func testCustomizedTargetGroupBindingObjectSelector() *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{"networking.olm.openshift.io/aws-load-balancer-controller": "internal"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      "example.org/tier",
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{"backend"},
+			},
+		},
+	}
+}
+
+func testPlatformNamespacesExcludedSelector() *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      "kubernetes.io/metadata.name",
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{"default", "kube-node-lease", "kube-public", "kube-system"},
+			},
+			{
+				Key:      "openshift.io/cluster-monitoring",
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{"true"},
+			},
+			{
+				Key:      "openshift.io/run-level",
+				Operator: metav1.LabelSelectorOpDoesNotExist,
+			},
+		},
+	}
+}
+
+func TestPlatformNamespacesExcludedSelector(t *testing.T) {
+	selector, err := metav1.LabelSelectorAsSelector(platformNamespacesExcludedSelector())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, tc := range []struct {
+		name            string
+		namespaceLabels labels.Set
+		expectedMatch   bool
+	}{
+		{
+			name:            "kube-system namespace",
+			namespaceLabels: labels.Set{"kubernetes.io/metadata.name": "kube-system"},
+		},
+		{
+			name:            "default namespace",
+			namespaceLabels: labels.Set{"kubernetes.io/metadata.name": "default"},
+		},
+		{
+			name:            "cluster monitoring namespace",
+			namespaceLabels: labels.Set{"kubernetes.io/metadata.name": "openshift-monitoring", "openshift.io/cluster-monitoring": "true"},
+		},
+		{
+			name:            "run level namespace",
+			namespaceLabels: labels.Set{"kubernetes.io/metadata.name": "openshift-kube-apiserver", "openshift.io/run-level": "0"},
+		},
+		{
+			name:            "empty run level namespace",
+			namespaceLabels: labels.Set{"kubernetes.io/metadata.name": "openshift-etcd", "openshift.io/run-level": ""},
+		},
+		{
+			name:            "unlabeled openshift namespace",
+			namespaceLabels: labels.Set{"kubernetes.io/metadata.name": "openshift-foo"},
+			expectedMatch:   true,
+		},
+		{
+			name:            "user namespace",
+			namespaceLabels: labels.Set{"kubernetes.io/metadata.name": "echoserver"},
+			expectedMatch:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if match := selector.Matches(tc.namespaceLabels); match != tc.expectedMatch {
+				t.Errorf("unexpected match of namespace labels %q, expected %t, got %t", tc.namespaceLabels, tc.expectedMatch, match)
+			}
+		})
+	}
+}
+
 func TestEnsureWebhooks(t *testing.T) {
 	for _, tc := range []struct {
 		name            string
@@ -483,6 +763,51 @@ func TestEnsureWebhooks(t *testing.T) {
 		expectedMWC     *arv1.MutatingWebhookConfiguration
 		existingObjects []client.Object
 	}{
+		{
+			name:       "no existing webhooks, default instance with other instances",
+			controller: &albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+			existingObjects: []client.Object{
+				&albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "internal"}},
+				&albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "external"}},
+			},
+			webhookService: &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: "test-namespace"}},
+			expectedVWC: &arv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aws-load-balancer-controller-cluster",
+					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+				},
+				Webhooks: testValidatingWebhooks("test-service", "test-namespace", testOtherInstancesExcludedObjectSelector("external", "internal")),
+			},
+			expectedMWC: &arv1.MutatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aws-load-balancer-controller-cluster",
+					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+				},
+				Webhooks: testMutatingWebhooks("test-service", "test-namespace", testOtherInstancesExcludedObjectSelector("external", "internal")),
+			},
+		},
+		{
+			name:       "no existing webhooks, oldest instance without default instance",
+			controller: &albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "internal", CreationTimestamp: metav1.NewTime(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))}},
+			existingObjects: []client.Object{
+				&albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "external", CreationTimestamp: metav1.NewTime(time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC))}},
+			},
+			webhookService: &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: "test-namespace"}},
+			expectedVWC: &arv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aws-load-balancer-controller-internal",
+					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+				},
+				Webhooks: testValidatingWebhooks("test-service", "test-namespace", testOtherInstancesExcludedObjectSelector("external")),
+			},
+			expectedMWC: &arv1.MutatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aws-load-balancer-controller-internal",
+					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+				},
+				Webhooks: testMutatingWebhooks("test-service", "test-namespace", testOtherInstancesExcludedObjectSelector("external")),
+			},
+		},
 		{
 			name:           "no existing webhooks",
 			controller:     &albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
@@ -492,14 +817,40 @@ func TestEnsureWebhooks(t *testing.T) {
 					Name:        "aws-load-balancer-controller-cluster",
 					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
 				},
-				Webhooks: testValidatingWebhooks("test-service", "test-namespace"),
+				Webhooks: testValidatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
 			},
 			expectedMWC: &arv1.MutatingWebhookConfiguration{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:        "aws-load-balancer-controller-cluster",
 					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
 				},
-				Webhooks: testMutatingWebhooks("test-service", "test-namespace"),
+				Webhooks: testMutatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
+			},
+		},
+		{
+			name:       "no existing webhooks, non default instance",
+			controller: &albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "internal"}},
+			existingObjects: []client.Object{
+				&albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+			},
+			webhookService: &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: "test-namespace"}},
+			expectedVWC: &arv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aws-load-balancer-controller-internal",
+					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+				},
+				Webhooks: testValidatingWebhooks("test-service", "test-namespace", &metav1.LabelSelector{
+					MatchLabels: map[string]string{"networking.olm.openshift.io/aws-load-balancer-controller": "internal"},
+				}),
+			},
+			expectedMWC: &arv1.MutatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aws-load-balancer-controller-internal",
+					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+				},
+				Webhooks: testMutatingWebhooks("test-service", "test-namespace", &metav1.LabelSelector{
+					MatchLabels: map[string]string{"networking.olm.openshift.io/aws-load-balancer-controller": "internal"},
+				}),
 			},
 		},
 		{
@@ -524,14 +875,14 @@ func TestEnsureWebhooks(t *testing.T) {
 					Name:        "aws-load-balancer-controller-cluster",
 					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
 				},
-				Webhooks: testValidatingWebhooks("test-service", "test-namespace"),
+				Webhooks: testValidatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
 			},
 			expectedMWC: &arv1.MutatingWebhookConfiguration{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:        "aws-load-balancer-controller-cluster",
 					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
 				},
-				Webhooks: testMutatingWebhooks("test-service", "test-namespace"),
+				Webhooks: testMutatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
 			},
 		},
 		{
@@ -556,14 +907,14 @@ func TestEnsureWebhooks(t *testing.T) {
 					Name:        "aws-load-balancer-controller-cluster",
 					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
 				},
-				Webhooks: testValidatingWebhooks("test-service", "test-namespace"),
+				Webhooks: testValidatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
 			},
 			expectedMWC: &arv1.MutatingWebhookConfiguration{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:        "aws-load-balancer-controller-cluster",
 					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
 				},
-				Webhooks: testMutatingWebhooks("test-service", "test-namespace"),
+				Webhooks: testMutatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
 			},
 		},
 		{
@@ -606,7 +957,7 @@ func TestEnsureWebhooks(t *testing.T) {
 						"test-key":                  "test-value",
 					},
 				},
-				Webhooks: testValidatingWebhooks("test-service", "test-namespace"),
+				Webhooks: testValidatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
 			},
 			expectedMWC: &arv1.MutatingWebhookConfiguration{
 				ObjectMeta: metav1.ObjectMeta{
@@ -616,7 +967,168 @@ func TestEnsureWebhooks(t *testing.T) {
 						"test-key":                  "test-value",
 					},
 				},
-				Webhooks: testMutatingWebhooks("test-service", "test-namespace"),
+				Webhooks: testMutatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
+			},
+		},
+		{
+			name: "pod readiness gate injection enabled",
+			controller: &albo.AWSLoadBalancerController{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec:       albo.AWSLoadBalancerControllerSpec{PodReadinessGateInjection: albo.PodReadinessGateInjectionEnabled},
+			},
+			existingObjects: []client.Object{
+				&arv1.MutatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "aws-load-balancer-controller-cluster",
+						Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+						OwnerReferences: []metav1.OwnerReference{
+							{Name: "cluster", Kind: "AWSLoadBalancerController"},
+						},
+					},
+					Webhooks: testMutatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
+				},
+			},
+			webhookService: &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: "test-namespace"}},
+			expectedVWC: &arv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aws-load-balancer-controller-cluster",
+					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+				},
+				Webhooks: testValidatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
+			},
+			expectedMWC: &arv1.MutatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aws-load-balancer-controller-cluster",
+					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+				},
+				Webhooks: append(testMutatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
+					testPodMutatingWebhook("test-service", "test-namespace")),
+			},
+		},
+		{
+			name: "default load balancer class enabled",
+			controller: &albo.AWSLoadBalancerController{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec:       albo.AWSLoadBalancerControllerSpec{DefaultLoadBalancerClass: albo.DefaultLoadBalancerClassEnabled},
+			},
+			webhookService: &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: "test-namespace"}},
+			expectedVWC: &arv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aws-load-balancer-controller-cluster",
+					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+				},
+				Webhooks: testValidatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
+			},
+			expectedMWC: &arv1.MutatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aws-load-balancer-controller-cluster",
+					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+				},
+				Webhooks: append(testMutatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
+					testServiceMutatingWebhook("test-service", "test-namespace")),
+			},
+		},
+		{
+			name: "customized webhooks",
+			controller: &albo.AWSLoadBalancerController{
+				ObjectMeta: metav1.ObjectMeta{Name: "internal"},
+				Spec: albo.AWSLoadBalancerControllerSpec{
+					PodReadinessGateInjection: albo.PodReadinessGateInjectionEnabled,
+					Webhooks: &albo.AWSLoadBalancerControllerWebhooks{
+						Ingress: &albo.AWSLoadBalancerWebhookConfig{
+							FailurePolicy: albo.WebhookFailurePolicyIgnore,
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"example.org/team": "web"},
+							},
+							TimeoutSeconds: 5,
+						},
+						TargetGroupBinding: &albo.AWSLoadBalancerWebhookConfig{
+							ObjectSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"example.org/tier": "backend"},
+							},
+						},
+						Pod: &albo.AWSLoadBalancerWebhookConfig{
+							FailurePolicy: albo.WebhookFailurePolicyIgnore,
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"example.org/team": "web"},
+							},
+						},
+					},
+				},
+			},
+			existingObjects: []client.Object{
+				&albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+			},
+			webhookService: &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: "test-namespace"}},
+			expectedVWC: &arv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aws-load-balancer-controller-internal",
+					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+				},
+				Webhooks: func() []arv1.ValidatingWebhook {
+					webhooks := testValidatingWebhooks("test-service", "test-namespace", testCustomizedTargetGroupBindingObjectSelector())
+					webhooks[1].FailurePolicy = failurePolicyPtr(arv1.Ignore)
+					webhooks[1].NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"example.org/team": "web"}}
+					webhooks[1].TimeoutSeconds = ptr.To[int32](5)
+					return webhooks
+				}(),
+			},
+			expectedMWC: &arv1.MutatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aws-load-balancer-controller-internal",
+					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+				},
+				Webhooks: func() []arv1.MutatingWebhook {
+					podWebhook := testPodMutatingWebhook("test-service", "test-namespace")
+					podWebhook.FailurePolicy = failurePolicyPtr(arv1.Ignore)
+					podWebhook.NamespaceSelector = &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{
+								Key:      "elbv2.k8s.aws/pod-readiness-gate-inject",
+								Operator: metav1.LabelSelectorOpIn,
+								Values:   []string{"enabled"},
+							},
+							{
+								Key:      "example.org/team",
+								Operator: metav1.LabelSelectorOpIn,
+								Values:   []string{"web"},
+							},
+						},
+					}
+					return append(testMutatingWebhooks("test-service", "test-namespace", testCustomizedTargetGroupBindingObjectSelector()), podWebhook)
+				}(),
+			},
+		},
+		{
+			name:       "pod readiness gate injection disabled",
+			controller: &albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+			existingObjects: []client.Object{
+				&arv1.MutatingWebhookConfiguration{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "aws-load-balancer-controller-cluster",
+						Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+						OwnerReferences: []metav1.OwnerReference{
+							{Name: "cluster", Kind: "AWSLoadBalancerController"},
+						},
+					},
+					Webhooks: append(testMutatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
+						testPodMutatingWebhook("test-service", "test-namespace")),
+				},
+			},
+			webhookService: &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "test-service", Namespace: "test-namespace"}},
+			expectedVWC: &arv1.ValidatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aws-load-balancer-controller-cluster",
+					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+				},
+				Webhooks: testValidatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
+			},
+			expectedMWC: &arv1.MutatingWebhookConfiguration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "aws-load-balancer-controller-cluster",
+					Annotations: map[string]string{injectCABundleAnnotationKey: injectCABundleAnnotationValue},
+				},
+				Webhooks: testMutatingWebhooks("test-service", "test-namespace", testDefaultInstanceObjectSelector()),
 			},
 		},
 	} {
@@ -681,3 +1193,91 @@ func hasOwner(controller *albo.AWSLoadBalancerController, references []metav1.Ow
 	}
 	return false
 }
+
+func TestTargetGroupBindingObjectSelector(t *testing.T) {
+	older := metav1.NewTime(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC))
+	newer := metav1.NewTime(older.Add(time.Hour))
+	testController := func(name string, created metav1.Time) *albo.AWSLoadBalancerController {
+		return &albo.AWSLoadBalancerController{ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: created}}
+	}
+	// the labels set by the controllers on the TargetGroupBindings they create for the Ingresses and the Services
+	ingressTGBLabels := labels.Set{"ingress.k8s.aws/stack-namespace": "echoserver", "ingress.k8s.aws/stack-name": "echoserver", "ingress.k8s.aws/resource": "echoserver-echoserver-80"}
+	serviceTGBLabels := labels.Set{"service.k8s.aws/stack-namespace": "echoserver", "service.k8s.aws/stack-name": "echoserver", "service.k8s.aws/resource": "80"}
+
+	for _, tc := range []struct {
+		name      string
+		instances []*albo.AWSLoadBalancerController
+		// expectedHandlers maps the labels of a TargetGroupBinding to the instance expected to handle it
+		expectedHandlers map[string]string
+	}{
+		{
+			name:      "default and other instance",
+			instances: []*albo.AWSLoadBalancerController{testController("internal", older), testController("cluster", newer)},
+			expectedHandlers: map[string]string{
+				ingressTGBLabels.String():                                   "cluster",
+				serviceTGBLabels.String():                                   "cluster",
+				labels.Set{controllerInstanceLabelKey: "internal"}.String(): "internal",
+				labels.Set{controllerInstanceLabelKey: "unknown"}.String():  "cluster",
+				labels.Set{}.String():                                       "cluster",
+			},
+		},
+		{
+			name:      "no default instance",
+			instances: []*albo.AWSLoadBalancerController{testController("internal", newer), testController("external", older)},
+			expectedHandlers: map[string]string{
+				ingressTGBLabels.String():                                   "external",
+				serviceTGBLabels.String():                                   "external",
+				labels.Set{controllerInstanceLabelKey: "internal"}.String(): "internal",
+				labels.Set{controllerInstanceLabelKey: "external"}.String(): "external",
+			},
+		},
+		{
+			name:      "single non default instance",
+			instances: []*albo.AWSLoadBalancerController{testController("internal", older)},
+			expectedHandlers: map[string]string{
+				ingressTGBLabels.String():                                   "internal",
+				serviceTGBLabels.String():                                   "internal",
+				labels.Set{controllerInstanceLabelKey: "internal"}.String(): "internal",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var objects []client.Object
+			for _, instance := range tc.instances {
+				objects = append(objects, instance)
+			}
+			r := &AWSLoadBalancerControllerReconciler{
+				Client: fake.NewClientBuilder().WithScheme(test.Scheme).WithObjects(objects...).Build(),
+				Scheme: test.Scheme,
+			}
+			selectors := map[string]labels.Selector{}
+			for _, instance := range tc.instances {
+				labelSelector, err := r.targetGroupBindingObjectSelector(context.Background(), instance)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+				if err != nil {
+					t.Fatalf("invalid selector for instance %q: %v", instance.Name, err)
+				}
+				selectors[instance.Name] = selector
+			}
+
+			for tgbLabels, expectedHandler := range tc.expectedHandlers {
+				set, err := labels.ConvertSelectorToLabelsMap(tgbLabels)
+				if err != nil {
+					t.Fatalf("invalid labels %q: %v", tgbLabels, err)
+				}
+				var handlers []string
+				for name, selector := range selectors {
+					if selector.Matches(set) {
+						handlers = append(handlers, name)
+					}
+				}
+				if len(handlers) != 1 || handlers[0] != expectedHandler {
+					t.Errorf("expected TargetGroupBinding with labels %q to be handled by %q only, got %v", tgbLabels, expectedHandler, handlers)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+	ec2fake "github.com/openshift/aws-load-balancer-operator/pkg/aws/fake"
+	albc "github.com/openshift/aws-load-balancer-operator/pkg/controllers/awsloadbalancercontroller"
+)
+
+const (
+	publicELBTagKey   = "kubernetes.io/role/elb"
+	internalELBTagKey = "kubernetes.io/role/internal-elb"
+)
+
+var _ = Describe("Subnet tagging", func() {
+	var ctx = context.Background()
+
+	getController := func(name string) *albo.AWSLoadBalancerController {
+		controller := &albo.AWSLoadBalancerController{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name}, controller)).To(Succeed())
+		return controller
+	}
+
+	It("tags the subnets and removes the tags on deletion", func() {
+		ec2Client := newFakeEC2()
+		r := newReconciler(ec2Client)
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "cluster"}}
+
+		Expect(k8sClient.Create(ctx, &albo.AWSLoadBalancerController{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec:       albo.AWSLoadBalancerControllerSpec{SubnetTagging: albo.AutoSubnetTaggingPolicy, IngressClass: "alb"},
+		})).To(Succeed())
+
+		By("reconciling until the credentials secret is awaited")
+		result, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+		Expect(ec2Client.Tags("subnet-public")).To(HaveKeyWithValue(publicELBTagKey, "1"))
+		Expect(ec2Client.Tags("subnet-private")).To(HaveKeyWithValue(internalELBTagKey, "1"))
+		controller := getController("cluster")
+		Expect(controller.Status.Subnets).NotTo(BeNil())
+		Expect(controller.Status.Subnets.Public).To(ConsistOf("subnet-public"))
+		Expect(controller.Status.Subnets.Internal).To(ConsistOf("subnet-private"))
+		Expect(meta.IsStatusConditionTrue(controller.Status.Conditions, albc.SubnetsAvailableCondition)).To(BeTrue())
+
+		By("deleting the controller")
+		Expect(k8sClient.Delete(ctx, controller)).To(Succeed())
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ec2Client.Tags("subnet-public")).NotTo(HaveKey(publicELBTagKey))
+		Expect(ec2Client.Tags("subnet-private")).NotTo(HaveKey(internalELBTagKey))
+		err = k8sClient.Get(ctx, req.NamespacedName, &albo.AWSLoadBalancerController{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("requeues the reconciliation while the EC2 API is throttled", func() {
+		ec2Client := newFakeEC2()
+		ec2Client.InjectFault(ec2fake.Fault{Operation: "CreateTags", Err: ec2fake.ThrottlingError(), Count: 1})
+		r := newReconciler(ec2Client)
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "throttled"}}
+
+		Expect(k8sClient.Create(ctx, &albo.AWSLoadBalancerController{
+			ObjectMeta: metav1.ObjectMeta{Name: "throttled"},
+			Spec:       albo.AWSLoadBalancerControllerSpec{SubnetTagging: albo.AutoSubnetTaggingPolicy, IngressClass: "alb-throttled", LoadBalancerClass: "example.com/throttled"},
+		})).To(Succeed())
+
+		By("reconciling while CreateTags is throttled")
+		result, err := r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		condition := meta.FindStatusCondition(getController("throttled").Status.Conditions, albc.SubnetsAvailableCondition)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("AWSAPIThrottled"))
+
+		By("reconciling once the throttling stopped")
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ec2Client.Tags("subnet-public")).To(HaveKeyWithValue(publicELBTagKey, "1"))
+		Expect(ec2Client.Tags("subnet-private")).To(HaveKeyWithValue(internalELBTagKey, "1"))
+		Expect(meta.IsStatusConditionTrue(getController("throttled").Status.Conditions, albc.SubnetsAvailableCondition)).To(BeTrue())
+	})
+})
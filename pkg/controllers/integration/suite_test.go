@@ -0,0 +1,135 @@
+package integration
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	awstypes "github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+
+	cco "github.com/openshift/cloud-credential-operator/pkg/apis/cloudcredential/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	elbv1beta1 "sigs.k8s.io/aws-load-balancer-controller/apis/elbv2/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	albo "github.com/openshift/aws-load-balancer-operator/api/v1"
+	"github.com/openshift/aws-load-balancer-operator/pkg/aws"
+	ec2fake "github.com/openshift/aws-load-balancer-operator/pkg/aws/fake"
+	albc "github.com/openshift/aws-load-balancer-operator/pkg/controllers/awsloadbalancercontroller"
+)
+
+// These tests run the reconciliation of the AWSLoadBalancerController against an envtest API server
+// and the in-memory EC2 of the fake package, without an AWS account.
+
+const (
+	operatorNamespace = "aws-load-balancer-operator"
+	clusterName       = "test-cluster"
+	vpcID             = "vpc-1"
+)
+
+var (
+	k8sClient client.Client
+	testEnv   *envtest.Environment
+)
+
+func TestIntegration(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Integration Suite")
+}
+
+var _ = BeforeSuite(func() {
+	logf.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
+
+	By("bootstrapping test environment")
+	testEnv = &envtest.Environment{
+		CRDDirectoryPaths: []string{
+			filepath.Join("..", "..", "..", "config", "crd", "bases"),
+			filepath.Join("..", "..", "utils", "test", "crd"),
+		},
+		ErrorIfCRDPathMissing: true,
+	}
+
+	cfg, err := testEnv.Start()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(cfg).NotTo(BeNil())
+
+	Expect(albo.AddToScheme(scheme.Scheme)).To(Succeed())
+	Expect(cco.Install(scheme.Scheme)).To(Succeed())
+	Expect(configv1.Install(scheme.Scheme)).To(Succeed())
+	Expect(elbv1beta1.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme.Scheme})
+	Expect(err).NotTo(HaveOccurred())
+
+	ctx := context.Background()
+	Expect(k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: operatorNamespace}})).To(Succeed())
+
+	infra := &configv1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	Expect(k8sClient.Create(ctx, infra)).To(Succeed())
+	infra.Status = configv1.InfrastructureStatus{
+		InfrastructureName: clusterName,
+		PlatformStatus: &configv1.PlatformStatus{
+			Type: configv1.AWSPlatformType,
+			AWS:  &configv1.AWSPlatformStatus{Region: "us-east-1"},
+		},
+	}
+	Expect(k8sClient.Status().Update(ctx, infra)).To(Succeed())
+}, 60)
+
+var _ = AfterSuite(func() {
+	By("tearing down the test environment")
+	Expect(testEnv.Stop()).To(Succeed())
+})
+
+// newFakeEC2 returns a fake EC2 holding the VPC of the cluster with a public and a private subnet.
+// The public subnet routes to an internet gateway, the private one uses the main route table of the VPC.
+func newFakeEC2() *ec2fake.EC2 {
+	clusterTag := ec2types.Tag{Key: awstypes.String("kubernetes.io/cluster/" + clusterName), Value: awstypes.String("owned")}
+	f := ec2fake.NewEC2()
+	f.PageSize = 1
+	f.AddVPC(ec2types.Vpc{VpcId: awstypes.String(vpcID), Tags: []ec2types.Tag{clusterTag}})
+	for _, id := range []string{"subnet-public", "subnet-private"} {
+		f.AddSubnet(ec2types.Subnet{SubnetId: awstypes.String(id), VpcId: awstypes.String(vpcID), Tags: []ec2types.Tag{clusterTag}})
+	}
+	f.AddRouteTable(ec2types.RouteTable{
+		RouteTableId: awstypes.String("rtb-public"),
+		VpcId:        awstypes.String(vpcID),
+		Associations: []ec2types.RouteTableAssociation{{SubnetId: awstypes.String("subnet-public")}},
+		Routes:       []ec2types.Route{{GatewayId: awstypes.String("igw-1"), State: ec2types.RouteStateActive}},
+	})
+	f.AddRouteTable(ec2types.RouteTable{
+		RouteTableId: awstypes.String("rtb-main"),
+		VpcId:        awstypes.String(vpcID),
+		Associations: []ec2types.RouteTableAssociation{{Main: awstypes.Bool(true)}},
+	})
+	return f
+}
+
+// newReconciler returns a reconciler using the given fake EC2 through the instrumented client,
+// like the operator does with the AWS SDK client.
+func newReconciler(ec2Client *ec2fake.EC2) *albc.AWSLoadBalancerControllerReconciler {
+	return &albc.AWSLoadBalancerControllerReconciler{
+		Client:      k8sClient,
+		Scheme:      scheme.Scheme,
+		Namespace:   operatorNamespace,
+		Image:       "quay.io/test/aws-load-balancer-controller:latest",
+		EC2Client:   aws.NewInstrumentedClient(ec2Client),
+		ClusterName: clusterName,
+		VPCID:       vpcID,
+		AWSRegion:   "us-east-1",
+		Recorder:    record.NewFakeRecorder(100),
+	}
+}
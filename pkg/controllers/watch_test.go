@@ -19,18 +19,30 @@ import (
 
 var _ = Describe("AWS Load Balancer Reconciler Watch Predicates", func() {
 	Context("AWS Load Balancer Controller", func() {
-		It("does not match the unique name", func() {
+		It("matches any instance name", func() {
 			albc := &albo.AWSLoadBalancerController{
 				ObjectMeta: metav1.ObjectMeta{
-					Name: "wrongname",
+					Name: "internal",
+				},
+			}
+			expectedReq := ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Name: "internal",
 				},
 			}
 			var gotReq ctrl.Request
-			errCh := waitForRequest(reconcileCollector.Requests, 1*time.Second, &gotReq)
+			errCh := waitForRequest(reconcileCollector.Requests, 2*time.Second, &gotReq)
 			Expect(k8sClient.Create(context.Background(), albc)).Should(Succeed())
-			Expect(<-errCh).NotTo(BeNil())
+			Expect(<-errCh).To(BeNil())
+			Expect(gotReq).To(Equal(expectedReq))
+
+			// remove the instance so that it's not enqueued by the other watches
+			errCh = waitForRequest(reconcileCollector.Requests, 2*time.Second, &gotReq)
+			Expect(k8sClient.Delete(context.Background(), albc)).Should(Succeed())
+			Expect(<-errCh).To(BeNil())
+			Expect(gotReq).To(Equal(expectedReq))
 		})
-		It("matches the unique name", func() {
+		It("matches the default instance name", func() {
 			albc := &albo.AWSLoadBalancerController{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "cluster",
@@ -16,6 +16,8 @@ func GetIAMPolicy() IAMPolicy {
 				PolicyCondition: cco.IAMPolicyCondition{},
 				Action: []string{
 					"ec2:DescribeSubnets",
+					"ec2:DescribeRouteTables",
+					"ec2:DescribeInstances",
 				},
 			},
 			{